@@ -0,0 +1,123 @@
+package nexmo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// tokenBucket rate limiter allowing rate events per second with
+// bursts of burst events.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// now and sleep are replaced in tests.
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+	sync.Mutex
+}
+
+// newTokenBucket returns a full token bucket.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+		sleep:  sleep,
+	}
+}
+
+// sleep waits d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// refill adds tokens earned since last refill, b must be locked.
+func (b *tokenBucket) refill() {
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// reserve takes n tokens and returns how long the caller must wait
+// before using them.
+func (b *tokenBucket) reserve(n float64) time.Duration {
+	b.Lock()
+	defer b.Unlock()
+	b.refill()
+	b.tokens -= n
+	if b.tokens >= 0 || b.rate <= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns n tokens taken with reserve.
+func (b *tokenBucket) cancel(n float64) {
+	b.Lock()
+	b.tokens += n
+	b.Unlock()
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	return b.WaitN(ctx, 1)
+}
+
+// WaitN blocks until n tokens are available or ctx is done.
+func (b *tokenBucket) WaitN(ctx context.Context, n int) error {
+	d := b.reserve(float64(n))
+	if d == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && b.now().Add(d).After(deadline) {
+		b.cancel(float64(n))
+		return context.DeadlineExceeded
+	}
+	if err := b.sleep(ctx, d); err != nil {
+		b.cancel(float64(n))
+		return err
+	}
+	return nil
+}
+
+// scaleRate multiplies rate by factor within [min, max] keeping
+// current tokens.
+func (b *tokenBucket) scaleRate(factor, min, max float64) {
+	b.Lock()
+	defer b.Unlock()
+	b.refill()
+	rate := b.rate * factor
+	if rate < min {
+		rate = min
+	}
+	if rate > max {
+		rate = max
+	}
+	b.rate = rate
+}
+
+// Rate returns current rate in events per second.
+func (b *tokenBucket) Rate() float64 {
+	b.Lock()
+	defer b.Unlock()
+	return b.rate
+}
@@ -0,0 +1,189 @@
+package nexmo
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/jimmy-go/nexmo/sms"
+)
+
+// BulkSender sends many SMS with a pool of workers limited to a
+// number of messages per second, Nexmo throttles at account level.
+type BulkSender struct {
+	client  *Nexmo
+	workers int
+	limiter *tokenBucket
+	stats   BulkStats
+	sync.Mutex
+}
+
+// BulkResult result of a single request sent by BulkSender.
+type BulkResult struct {
+	// Index request position in the input.
+	Index int
+
+	Request  *sms.Request
+	Messages []*sms.Message
+	Err      error
+}
+
+// BulkStats aggregated BulkSender results.
+type BulkStats struct {
+	Sent   int
+	Failed int
+
+	// TotalPrice sum of message-price of every message.
+	TotalPrice float64
+
+	// RemainingBalance lowest remaining-balance reported, valid
+	// when BalanceReported is true.
+	RemainingBalance float64
+	BalanceReported  bool
+}
+
+// NewBulkSender returns a BulkSender with workers goroutines sending
+// at most perSecond messages per second, each part of a long text
+// counts as a message. perSecond <= 0 means no limit.
+func NewBulkSender(client *Nexmo, workers int, perSecond float64) *BulkSender {
+	if workers < 1 {
+		workers = 1
+	}
+	b := &BulkSender{
+		client:  client,
+		workers: workers,
+	}
+	if perSecond > 0 {
+		b.limiter = newTokenBucket(perSecond, 1)
+	}
+	return b
+}
+
+// SendAll sends reqs, see Send.
+func (b *BulkSender) SendAll(ctx context.Context, reqs []*sms.Request) <-chan *BulkResult {
+	ch := make(chan *sms.Request)
+	go func() {
+		defer close(ch)
+		for _, r := range reqs {
+			select {
+			case ch <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return b.Send(ctx, ch)
+}
+
+// Send sends every request received from reqs until it is closed
+// or ctx is done. Results are streamed in completion order and the
+// returned channel is closed when every worker finished. Stats only
+// count results delivered, a result dropped because ctx is done is
+// not counted.
+func (b *BulkSender) Send(ctx context.Context, reqs <-chan *sms.Request) <-chan *BulkResult {
+	type job struct {
+		index int
+		req   *sms.Request
+	}
+	jobs := make(chan job)
+	results := make(chan *BulkResult)
+	go func() {
+		defer close(jobs)
+		i := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case r, ok := <-reqs:
+				if !ok {
+					return
+				}
+				select {
+				case jobs <- job{i, r}:
+					i++
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	var wg sync.WaitGroup
+	for w := 0; w < b.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				res := b.send(ctx, j.index, j.req)
+				select {
+				case results <- res:
+					b.record(res)
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// send sends a single request.
+func (b *BulkSender) send(ctx context.Context, index int, r *sms.Request) *BulkResult {
+	res := &BulkResult{
+		Index:   index,
+		Request: r,
+	}
+	if b.limiter != nil {
+		res.Err = b.limiter.WaitN(ctx, segments(r))
+	}
+	if res.Err == nil {
+		var resp *sms.Response
+		resp, res.Err = b.client.SMSContext(ctx, r)
+		if resp != nil {
+			res.Messages = resp.Messages
+			if res.Err == nil {
+				res.Err = resp.Err()
+			}
+		}
+	}
+	return res
+}
+
+// segments returns messages billed for r.
+func segments(r *sms.Request) int {
+	switch r.Type {
+	case "", sms.TypeText, sms.TypeUnicode:
+		return sms.Analyze(r.Text).Segments
+	}
+	return 1
+}
+
+// record adds res to stats.
+func (b *BulkSender) record(res *BulkResult) {
+	b.Lock()
+	defer b.Unlock()
+	if res.Err != nil {
+		b.stats.Failed++
+	} else {
+		b.stats.Sent++
+	}
+	for _, m := range res.Messages {
+		if price, err := strconv.ParseFloat(m.MessagePrice, 64); err == nil {
+			b.stats.TotalPrice += price
+		}
+		balance, err := strconv.ParseFloat(m.RemainingBalance, 64)
+		if err == nil && (balance < b.stats.RemainingBalance || !b.stats.BalanceReported) {
+			b.stats.RemainingBalance = balance
+			b.stats.BalanceReported = true
+		}
+	}
+}
+
+// Stats returns aggregated results so far.
+func (b *BulkSender) Stats() BulkStats {
+	b.Lock()
+	defer b.Unlock()
+	return b.stats
+}
@@ -25,6 +25,6 @@ type Request struct {
 type Response struct {
 	CallID    string `json:"call-id"`
 	To        string `json:"to"`
-	Status    int    `json:"status"`
+	Status    Status `json:"status"`
 	ErrorText string `json:"error-text"`
 }
@@ -0,0 +1,153 @@
+// Package call contains tests for call package.
+package call
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jimmy-go/nexmo/ncco"
+)
+
+func TestRouter(t *testing.T) {
+	var events []*Event
+	record := func(ctx context.Context, e *Event) error {
+		events = append(events, e)
+		return nil
+	}
+	rt := &Router{
+		OnAnswer: func(ctx context.Context, r *AnswerRequest) (Answer, error) {
+			if r.UUID == "xml" {
+				return XML("<vxml/>"), nil
+			}
+			return NCCO(ncco.New().Talk(ncco.Talk{Text: "Hello " + r.From})), nil
+		},
+		OnCompleted: record,
+		OnEvent:     record,
+	}
+
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", "/calls/answer?to=12015550123&from=5215522334455&uuid=a&conversation_uuid=b", nil))
+	if w.Code != 200 || w.Body.String() != `[{"action":"talk","text":"Hello 5215522334455"}]` {
+		t.Errorf("unexpected answer [%d] [%s]", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", "/calls/answer?uuid=xml", nil))
+	if w.Header().Get("Content-Type") != "application/xml" || w.Body.String() != "<vxml/>" {
+		t.Errorf("unexpected xml answer [%s]", w.Body.String())
+	}
+
+	body := `{"uuid":"a","conversation_uuid":"b","status":"completed","direction":"outbound",
+		"timestamp":"2017-01-01T12:00:30.000Z","start_time":"2017-01-01T12:00:00.000Z",
+		"end_time":"2017-01-01T12:00:30.000Z","duration":"30","price":"0.0025","rate":0.005,"network":"33402"}`
+	r := httptest.NewRequest("POST", "/calls/event", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	rt.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent || len(events) != 1 {
+		t.Fatalf("unexpected event response [%d] [%v]", w.Code, events)
+	}
+	e := events[0]
+	start := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	if e.Duration != 30*time.Second || e.Price != 0.0025 || e.Rate != 0.005 ||
+		!e.StartTime.Equal(start) || !e.EndTime.Equal(start.Add(30*time.Second)) {
+		t.Errorf("unexpected event [%+v]", e)
+	}
+
+	w = httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", "/calls/event?uuid=a&status=ringing", nil))
+	if w.Code != http.StatusNoContent || len(events) != 2 || events[1].Status != EventRinging {
+		t.Errorf("expected fallback event handler [%d] [%v]", w.Code, events)
+	}
+	var big Event
+	if err := json.Unmarshal([]byte(`{"uuid":12345678901234567890,"price":0.0000025}`), &big); err != nil ||
+		big.UUID != "12345678901234567890" || big.Price != 0.0000025 {
+		t.Errorf("unexpected numeric fields [%+v] [%v]", big, err)
+	}
+
+	w = httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", "/calls/unknown", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 actual [%d]", w.Code)
+	}
+}
+
+func TestCallbackHandler(t *testing.T) {
+	var got *CallbackEvent
+	h := NewCallbackHandler(func(ctx context.Context, e *CallbackEvent) error {
+		got = e
+		return nil
+	})
+	q := "/status?call-id=abc&to=12015550123&call-status=OK&call-request=2017-01-01+12:00:00" +
+		"&call-start=2017-01-01+12:00:05&call-end=2017-01-01+12:01:05&call-duration=60&call-price=0.012&call-rate=0.012"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", q, nil))
+	if w.Code != http.StatusNoContent || got == nil {
+		t.Fatalf("expected [%d] actual [%d]", http.StatusNoContent, w.Code)
+	}
+	if got.Status != CallbackOK || got.Duration != time.Minute || got.Price != 0.012 ||
+		got.EndTime.Sub(got.StartTime) != time.Minute {
+		t.Errorf("unexpected callback [%+v]", got)
+	}
+
+	r := httptest.NewRequest("POST", "/status", strings.NewReader(`{"call-id":"abc","status":"busy","call-duration":0}`))
+	r.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent || got.Status != CallbackBusy {
+		t.Errorf("expected [%v] actual [%v]", CallbackBusy, got.Status)
+	}
+
+	table := []struct {
+		Method   string
+		URL      string
+		Expected int
+	}{
+		{"GET", "/status?to=12015550123", http.StatusBadRequest},
+		{"PUT", "/status?call-id=abc", http.StatusMethodNotAllowed},
+	}
+	for _, m := range table {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(m.Method, m.URL, nil))
+		if w.Code != m.Expected {
+			t.Errorf("expected [%v] actual [%v]", m.Expected, w.Code)
+		}
+	}
+}
+
+func TestStatus(t *testing.T) {
+	table := []struct {
+		Input     string
+		Expected  Status
+		Temporary bool
+		Permanent bool
+	}{
+		{`{"status":0}`, StatusOK, false, false},
+		{`{"status":"1"}`, StatusThrottled, true, false},
+		{`{"status":4}`, StatusInvalidCredentials, false, true},
+	}
+	for i, x := range table {
+		var r Response
+		if err := json.Unmarshal([]byte(x.Input), &r); err != nil {
+			t.Fatalf("%d : unmarshal : %v", i, err)
+		}
+		if r.Status != x.Expected || r.Status.IsTemporary() != x.Temporary || r.Status.IsPermanent() != x.Permanent {
+			t.Errorf("%d : expected [%v] actual [%v]", i, x.Expected, r.Status)
+		}
+	}
+	r := Response{Status: StatusInternalError}
+	if err := json.Unmarshal([]byte(`{"status":null}`), &r); err != nil || r.Status != StatusInternalError {
+		t.Errorf("expected null to keep [%v] actual [%v] [%v]", StatusInternalError, r.Status, err)
+	}
+	if StatusThrottled.String() != "throttled" || Status(42).String() != "status(42)" {
+		t.Errorf("unexpected string [%s] [%s]", StatusThrottled, Status(42))
+	}
+	b, err := json.Marshal(&Response{Status: StatusThrottled})
+	if err != nil || !strings.Contains(string(b), `"status":1`) {
+		t.Errorf("unexpected json [%s] [%v]", b, err)
+	}
+}
@@ -0,0 +1,105 @@
+package call
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jimmy-go/nexmo/internal/webhook"
+)
+
+// ErrInvalidCallback callback request without call-id or with
+// an invalid payload.
+var ErrInvalidCallback = errors.New("nexmo: call : invalid callback")
+
+// CallbackStatus final call status sent to Request.StatusURL.
+type CallbackStatus string
+
+const (
+	// CallbackOK call was answered and completed.
+	CallbackOK CallbackStatus = "ok"
+
+	// CallbackBusy destination was busy.
+	CallbackBusy CallbackStatus = "busy"
+
+	// CallbackUnanswered destination did not answer.
+	CallbackUnanswered CallbackStatus = "unanswered"
+
+	// CallbackRejected destination rejected the call.
+	CallbackRejected CallbackStatus = "rejected"
+
+	// CallbackFailed call could not be placed.
+	CallbackFailed CallbackStatus = "failed"
+)
+
+// CallbackEvent Nexmo request to Request.StatusURL.
+//
+// see: https://docs.nexmo.com/voice/call/callbacks
+type CallbackEvent struct {
+	CallID      string
+	To          string
+	From        string
+	Status      CallbackStatus
+	Direction   string
+	NetworkCode string
+	RequestTime time.Time
+	StartTime   time.Time
+	EndTime     time.Time
+	Duration    time.Duration
+
+	// Price total call price.
+	Price float64
+
+	// Rate price per minute.
+	Rate float64
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *CallbackEvent) UnmarshalJSON(b []byte) error {
+	var raw webhook.Callback
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*e = CallbackEvent{
+		CallID:      string(raw.CallID),
+		To:          string(raw.To),
+		From:        string(raw.From),
+		Status:      CallbackStatus(raw.FinalStatus()),
+		Direction:   string(raw.Direction),
+		NetworkCode: string(raw.NetworkCode),
+		RequestTime: webhook.Time(string(raw.RequestTime)),
+		StartTime:   webhook.Time(string(raw.StartTime)),
+		EndTime:     webhook.Time(string(raw.EndTime)),
+		Duration:    webhook.Seconds(string(raw.Duration)),
+		Price:       webhook.Float(string(raw.Price)),
+		Rate:        webhook.Float(string(raw.Rate)),
+	}
+	return nil
+}
+
+// NewCallbackHandler returns an http.Handler for Request.StatusURL
+// requests sent with GET query or POST form or JSON, as set in
+// StatusMethod. It answers 204 when fn succeeds, 400 for invalid
+// payloads and 500 when fn fails so Nexmo retries.
+func NewCallbackHandler(fn func(ctx context.Context, e *CallbackEvent) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !webhook.MethodAllowed(r) {
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		var e CallbackEvent
+		err := webhook.Decode(r, &e)
+		if err != nil || len(e.CallID) < 1 {
+			http.Error(w, ErrInvalidCallback.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := fn(r.Context(), &e); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
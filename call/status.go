@@ -0,0 +1,114 @@
+package call
+
+import (
+	"strconv"
+
+	"github.com/jimmy-go/nexmo/internal/status"
+)
+
+// Status Nexmo Call response status code.
+//
+// see: https://docs.nexmo.com/voice/call/response
+type Status int
+
+const (
+	// StatusOK 0 - Success.
+	StatusOK Status = 0
+
+	// StatusThrottled 1 - Throttled, back-off and retry.
+	StatusThrottled Status = 1
+
+	// StatusMissingParams 2 - Missing mandatory parameters.
+	StatusMissingParams Status = 2
+
+	// StatusInvalidParams 3 - Invalid parameter value.
+	StatusInvalidParams Status = 3
+
+	// StatusInvalidCredentials 4 - Invalid api_key or api_secret.
+	StatusInvalidCredentials Status = 4
+
+	// StatusInternalError 5 - Nexmo internal error.
+	StatusInternalError Status = 5
+
+	// StatusInvalidMessage 6 - Unable to process request.
+	StatusInvalidMessage Status = 6
+
+	// StatusNumberBarred 7 - Destination is blacklisted.
+	StatusNumberBarred Status = 7
+
+	// StatusPartnerAccountBarred 8 - Account is barred.
+	StatusPartnerAccountBarred Status = 8
+
+	// StatusPartnerQuotaExceeded 9 - Not enough credit.
+	StatusPartnerQuotaExceeded Status = 9
+
+	// StatusAccountNotEnabled 11 - Account not enabled for Voice.
+	StatusAccountNotEnabled Status = 11
+
+	// StatusMessageTooLong 12 - Request too long.
+	StatusMessageTooLong Status = 12
+
+	// StatusCommunicationFailed 13 - Call could not be placed.
+	StatusCommunicationFailed Status = 13
+
+	// StatusInvalidSignature 14 - Invalid request signature.
+	StatusInvalidSignature Status = 14
+
+	// StatusInvalidSenderAddress 15 - Caller ID not allowed.
+	StatusInvalidSenderAddress Status = 15
+)
+
+// String returns the status name, e.g. "throttled".
+func (s Status) String() string {
+	return status.Name(int(s))
+}
+
+// Description returns the Nexmo description of the status.
+func (s Status) Description() string {
+	return status.Description(int(s))
+}
+
+// IsTemporary reports if the request can be retried later.
+func (s Status) IsTemporary() bool {
+	return status.Temporary(int(s))
+}
+
+// IsPermanent reports if the request failed and retrying
+// will not help.
+func (s Status) IsPermanent() bool {
+	return status.Permanent(int(s))
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Status) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(s))), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it accepts 1 and "1".
+// null leaves s unchanged.
+func (s *Status) UnmarshalJSON(b []byte) error {
+	if status.IsNull(b) {
+		return nil
+	}
+	n, err := status.ParseJSON(b)
+	if err != nil {
+		return err
+	}
+	*s = Status(n)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(int(s))), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Status) UnmarshalText(b []byte) error {
+	n, err := strconv.Atoi(string(b))
+	if err != nil {
+		return err
+	}
+	*s = Status(n)
+	return nil
+}
@@ -0,0 +1,55 @@
+package call
+
+import (
+	"fmt"
+
+	"github.com/jimmy-go/nexmo/internal/validate"
+)
+
+// FieldError names the request field that is not valid.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+// Error implements error interface.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("nexmo: call : field [%s] %s", e.Field, e.Reason)
+}
+
+// Validate checks request fields before sending.
+func (r *Request) Validate() error {
+	if !validate.Number(r.To) {
+		return &FieldError{"To", "must be an international number"}
+	}
+	if !validate.URL(r.AnswerURL) {
+		return &FieldError{"AnswerURL", "must be an absolute http URL"}
+	}
+	if len(r.From) > 0 && !validate.Number(r.From) {
+		return &FieldError{"From", "must be an international number"}
+	}
+	if len(r.MachineDetection) > 0 && r.MachineDetection != "true" && r.MachineDetection != "hangup" {
+		return &FieldError{"MachineDetection", "must be true or hangup"}
+	}
+	if len(r.MachineTimeout) > 0 && !validate.IntRange(r.MachineTimeout, 400, 10000) {
+		return &FieldError{"MachineTimeout", "must be between 400 and 10000 milliseconds"}
+	}
+	urls := []struct {
+		name   string
+		url    string
+		method string
+	}{
+		{"Answer", r.AnswerURL, r.AnswerMethod},
+		{"Error", r.ErrorURL, r.ErrorMethod},
+		{"Status", r.StatusURL, r.StatusMethod},
+	}
+	for _, u := range urls {
+		if len(u.url) > 0 && !validate.URL(u.url) {
+			return &FieldError{u.name + "URL", "must be an absolute http URL"}
+		}
+		if len(u.method) > 0 && !validate.Method(u.method) {
+			return &FieldError{u.name + "Method", "must be GET or POST"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package call
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/jimmy-go/nexmo/internal/webhook"
+	"github.com/jimmy-go/nexmo/ncco"
+)
+
+const (
+	// EventStarted call is created.
+	EventStarted = "started"
+
+	// EventRinging destination is ringing.
+	EventRinging = "ringing"
+
+	// EventAnswered call was answered.
+	EventAnswered = "answered"
+
+	// EventMachine answered by an answering machine.
+	EventMachine = "machine"
+
+	// EventCompleted call finished.
+	EventCompleted = "completed"
+
+	// EventBusy destination is busy.
+	EventBusy = "busy"
+
+	// EventCancelled call cancelled before answer.
+	EventCancelled = "cancelled"
+
+	// EventFailed call failed.
+	EventFailed = "failed"
+
+	// EventRejected call rejected.
+	EventRejected = "rejected"
+
+	// EventTimeout ringing timed out.
+	EventTimeout = "timeout"
+
+	// EventUnanswered call was not answered.
+	EventUnanswered = "unanswered"
+)
+
+// AnswerRequest Nexmo request to the answer URL.
+type AnswerRequest struct {
+	To               string `json:"to"`
+	From             string `json:"from"`
+	UUID             string `json:"uuid"`
+	ConversationUUID string `json:"conversation_uuid"`
+}
+
+// Event Nexmo request to the event or error URL.
+type Event struct {
+	UUID             string
+	ConversationUUID string
+	Status           string
+	Direction        string
+	To               string
+	From             string
+	Network          string
+	Timestamp        time.Time
+	StartTime        time.Time
+	EndTime          time.Time
+	Duration         time.Duration
+
+	// Price total call price.
+	Price float64
+
+	// Rate price per minute.
+	Rate float64
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Numbers can be sent
+// as strings and invalid times or numbers are left zero.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		UUID             webhook.String `json:"uuid"`
+		ConversationUUID webhook.String `json:"conversation_uuid"`
+		Status           webhook.String `json:"status"`
+		Direction        webhook.String `json:"direction"`
+		To               webhook.String `json:"to"`
+		From             webhook.String `json:"from"`
+		Network          webhook.String `json:"network"`
+		Timestamp        webhook.String `json:"timestamp"`
+		StartTime        webhook.String `json:"start_time"`
+		EndTime          webhook.String `json:"end_time"`
+		Duration         webhook.String `json:"duration"`
+		Price            webhook.String `json:"price"`
+		Rate             webhook.String `json:"rate"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*e = Event{
+		UUID:             string(raw.UUID),
+		ConversationUUID: string(raw.ConversationUUID),
+		Status:           string(raw.Status),
+		Direction:        string(raw.Direction),
+		To:               string(raw.To),
+		From:             string(raw.From),
+		Network:          string(raw.Network),
+		Timestamp:        webhook.Time(string(raw.Timestamp)),
+		StartTime:        webhook.Time(string(raw.StartTime)),
+		EndTime:          webhook.Time(string(raw.EndTime)),
+		Duration:         webhook.Seconds(string(raw.Duration)),
+		Price:            webhook.Float(string(raw.Price)),
+		Rate:             webhook.Float(string(raw.Rate)),
+	}
+	return nil
+}
+
+// Answer answer URL response.
+type Answer interface {
+	// WriteAnswer writes the response document.
+	WriteAnswer(w http.ResponseWriter) error
+}
+
+// NCCO returns n as an Answer.
+func NCCO(n *ncco.NCCO) Answer {
+	return nccoAnswer{n}
+}
+
+type nccoAnswer struct {
+	n *ncco.NCCO
+}
+
+// WriteAnswer implements Answer.
+func (a nccoAnswer) WriteAnswer(w http.ResponseWriter) error {
+	b, err := a.n.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(b)
+	return err
+}
+
+// XML VoiceXML answer document for legacy Call API.
+type XML string
+
+// WriteAnswer implements Answer.
+func (a XML) WriteAnswer(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/xml")
+	_, err := w.Write([]byte(a))
+	return err
+}
+
+// EventHandler handles call events.
+type EventHandler func(ctx context.Context, e *Event) error
+
+// Router serves answer, event and error URLs dispatching to typed
+// handlers. Nil handlers acknowledge the request.
+type Router struct {
+	// OnAnswer returns the document to control the call.
+	OnAnswer func(ctx context.Context, r *AnswerRequest) (Answer, error)
+
+	OnStarted   EventHandler
+	OnRinging   EventHandler
+	OnAnswered  EventHandler
+	OnMachine   EventHandler
+	OnCompleted EventHandler
+
+	// OnEvent handles events without a specific handler.
+	OnEvent EventHandler
+
+	// OnError handles requests to the error URL.
+	OnError EventHandler
+}
+
+// ServeHTTP routes requests by last path element: answer, event
+// or error, e.g. /calls/answer.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch path.Base(r.URL.Path) {
+	case "answer":
+		rt.AnswerHandler().ServeHTTP(w, r)
+	case "event":
+		rt.EventHandler().ServeHTTP(w, r)
+	case "error":
+		rt.ErrorHandler().ServeHTTP(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// AnswerHandler returns the answer URL http.Handler.
+func (rt *Router) AnswerHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var a AnswerRequest
+		if err := webhook.Decode(r, &a); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if rt.OnAnswer == nil {
+			http.Error(w, "nexmo: call : no answer handler", http.StatusInternalServerError)
+			return
+		}
+		doc, err := rt.OnAnswer(r.Context(), &a)
+		if err != nil || doc == nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if err := doc.WriteAnswer(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// EventHandler returns the event URL http.Handler.
+func (rt *Router) EventHandler() http.Handler {
+	return eventHandler(func(e *Event) EventHandler {
+		var h EventHandler
+		switch e.Status {
+		case EventStarted:
+			h = rt.OnStarted
+		case EventRinging:
+			h = rt.OnRinging
+		case EventAnswered:
+			h = rt.OnAnswered
+		case EventMachine:
+			h = rt.OnMachine
+		case EventCompleted:
+			h = rt.OnCompleted
+		}
+		if h == nil {
+			h = rt.OnEvent
+		}
+		return h
+	})
+}
+
+// ErrorHandler returns the error URL http.Handler.
+func (rt *Router) ErrorHandler() http.Handler {
+	return eventHandler(func(e *Event) EventHandler {
+		return rt.OnError
+	})
+}
+
+// eventHandler decodes events and calls the handler returned by
+// pick. It answers 204 on success and 500 when the handler fails.
+func eventHandler(pick func(e *Event) EventHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e Event
+		if err := webhook.Decode(r, &e); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if h := pick(&e); h != nil {
+			if err := h(r.Context(), &e); err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
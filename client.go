@@ -0,0 +1,37 @@
+package nexmo
+
+import (
+	"context"
+
+	"github.com/jimmy-go/nexmo/call"
+	"github.com/jimmy-go/nexmo/sms"
+	"github.com/jimmy-go/nexmo/text2speech"
+)
+
+// SMSSender sends SMS. See nexmotest.Fake for an in memory
+// implementation.
+type SMSSender interface {
+	SMS(r *sms.Request) (*sms.Response, error)
+	SMSContext(ctx context.Context, r *sms.Request) (*sms.Response, error)
+}
+
+// Caller makes outbound calls.
+type Caller interface {
+	Call(r *call.Request) (*call.Response, error)
+	CallContext(ctx context.Context, r *call.Request) (*call.Response, error)
+}
+
+// Speaker sends Text-To-Speech calls.
+type Speaker interface {
+	Text2Speech(r *text2speech.Request) (*text2speech.Response, error)
+	Text2SpeechContext(ctx context.Context, r *text2speech.Request) (*text2speech.Response, error)
+}
+
+// Client all features of Nexmo client.
+type Client interface {
+	SMSSender
+	Caller
+	Speaker
+}
+
+var _ Client = (*Nexmo)(nil)
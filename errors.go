@@ -0,0 +1,78 @@
+package nexmo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// APIError is returned when Nexmo answers with a non 200 status
+// or with a body that can not be decoded. It matches ErrBadRequest
+// with errors.Is when the status is not 200.
+type APIError struct {
+	// StatusCode HTTP status code.
+	StatusCode int
+
+	// Endpoint supportmap key: "sms", "call" or "text2speech", or
+	// "voice" for Voice API requests.
+	Endpoint string
+
+	// Body raw response body.
+	Body []byte
+
+	// ErrorText parsed Nexmo error-text or error_text if any.
+	ErrorText string
+
+	// RetryAfter parsed Retry-After header if any.
+	RetryAfter time.Duration
+
+	// Err decode error when status is 200 but body is invalid.
+	Err error
+}
+
+// Error implements error interface.
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("nexmo: %s : status [%d]", e.Endpoint, e.StatusCode)
+	if len(e.ErrorText) > 0 {
+		msg += fmt.Sprintf(" error text [%s]", e.ErrorText)
+	}
+	if e.Err != nil {
+		msg += fmt.Sprintf(" err [%v]", e.Err)
+	}
+	return msg
+}
+
+// Is reports ErrBadRequest for non 200 responses.
+func (e *APIError) Is(target error) bool {
+	return target == ErrBadRequest && e.StatusCode != http.StatusOK
+}
+
+// Unwrap returns the decode error if any.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// newAPIError builds an APIError parsing error text from body.
+func newAPIError(endpoint string, status int, body []byte, err error) *APIError {
+	e := &APIError{
+		StatusCode: status,
+		Endpoint:   endpoint,
+		Body:       body,
+		Err:        err,
+	}
+	var v struct {
+		Dash       string `json:"error-text"`
+		Underscore string `json:"error_text"`
+		Title      string `json:"title"`
+	}
+	if json.Unmarshal(body, &v) == nil {
+		for _, s := range []string{v.Dash, v.Underscore, v.Title} {
+			if len(s) > 0 {
+				e.ErrorText = s
+				break
+			}
+		}
+	}
+	return e
+}
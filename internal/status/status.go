@@ -0,0 +1,77 @@
+// Package status contains the status codes shared by the legacy
+// Call and Text-To-Speech APIs.
+//
+// see: https://docs.nexmo.com/voice/call/response
+package status
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Code describes a response status code.
+type Code struct {
+	Name        string
+	Description string
+	Temporary   bool
+}
+
+// Codes response status codes by number.
+var Codes = map[int]Code{
+	0:  {"success", "The request was successfully accepted for delivery by Nexmo.", false},
+	1:  {"throttled", "You have exceeded the submission capacity allowed on this account, please back-off and retry.", true},
+	2:  {"missing params", "Your request is incomplete and missing some mandatory parameters.", false},
+	3:  {"invalid params", "The value of one or more parameters is invalid.", false},
+	4:  {"invalid credentials", "The api_key / api_secret you supplied is either invalid or disabled.", false},
+	5:  {"internal error", "An error has occurred in the Nexmo platform whilst processing this request.", true},
+	6:  {"invalid message", "The Nexmo platform was unable to process this request.", false},
+	7:  {"number barred", "The number you are trying to call is blacklisted.", false},
+	8:  {"partner account barred", "The api_key you supplied is for an account that has been barred.", false},
+	9:  {"partner quota exceeded", "You do not have sufficient credit to make this request.", false},
+	11: {"account not enabled", "This account is not provisioned for the requested API.", false},
+	12: {"message too long", "The request length exceeds the maximum allowed.", false},
+	13: {"communication failed", "The call could not be placed, retry later.", true},
+	14: {"invalid signature", "The signature supplied could not be verified.", false},
+	15: {"invalid sender address", "You are using a caller ID that is not allowed.", false},
+}
+
+// Name returns the code name or "status(n)" for unknown codes.
+func Name(n int) string {
+	if c, ok := Codes[n]; ok {
+		return c.Name
+	}
+	return "status(" + strconv.Itoa(n) + ")"
+}
+
+// Description returns the code description, empty when unknown.
+func Description(n int) string {
+	return Codes[n].Description
+}
+
+// Temporary reports if the request can be retried later.
+func Temporary(n int) bool {
+	return Codes[n].Temporary
+}
+
+// Permanent reports if the request failed and will fail again.
+func Permanent(n int) bool {
+	return n != 0 && !Temporary(n)
+}
+
+// IsNull reports if b is JSON null, unmarshalers must leave the
+// value unchanged so a missing status is never read as success.
+func IsNull(b []byte) bool {
+	return string(b) == "null"
+}
+
+// ParseJSON parses a JSON number or a JSON string holding a number.
+func ParseJSON(b []byte) (int, error) {
+	s := string(b)
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return 0, err
+		}
+	}
+	return strconv.Atoi(strings.TrimSpace(s))
+}
@@ -0,0 +1,72 @@
+// Package validate contains field checks shared by request types.
+package validate
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Number reports if s is an international number in E.164 form
+// without spaces: optional + and 7 to 15 digits not starting with 0.
+func Number(s string) bool {
+	if len(s) > 0 && s[0] == '+' {
+		s = s[1:]
+	}
+	if len(s) < 7 || len(s) > 15 || s[0] == '0' {
+		return false
+	}
+	return Digits(s)
+}
+
+// Digits reports if s only has ASCII digits.
+func Digits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+// Sender reports if s is a valid sender: a number up to 15 digits
+// with optional + or up to 11 alphanumeric characters.
+func Sender(s string) bool {
+	if len(s) > 1 && s[0] == '+' && Digits(s[1:]) {
+		return len(s) <= 16
+	}
+	if Digits(s) {
+		return len(s) <= 15
+	}
+	if len(s) < 1 || len(s) > 11 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == ' ':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// URL reports if s is an absolute http or https URL.
+func URL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && len(u.Host) > 0
+}
+
+// Method reports if s is GET or POST.
+func Method(s string) bool {
+	return s == "GET" || s == "POST"
+}
+
+// IntRange reports if s is an integer between min and max.
+func IntRange(s string, min, max int) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= min && n <= max
+}
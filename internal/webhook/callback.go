@@ -0,0 +1,31 @@
+package webhook
+
+import "strings"
+
+// Callback raw legacy call or text-to-speech callback payload,
+// public packages convert it to their own CallbackEvent.
+type Callback struct {
+	CallID      String `json:"call-id"`
+	To          String `json:"to"`
+	From        String `json:"from"`
+	Status      String `json:"status"`
+	CallStatus  String `json:"call-status"`
+	Direction   String `json:"call-direction"`
+	NetworkCode String `json:"network-code"`
+	RequestTime String `json:"call-request"`
+	StartTime   String `json:"call-start"`
+	EndTime     String `json:"call-end"`
+	Duration    String `json:"call-duration"`
+	Price       String `json:"call-price"`
+	Rate        String `json:"call-rate"`
+}
+
+// FinalStatus returns call-status or status when missing, in
+// lower case.
+func (c *Callback) FinalStatus() string {
+	s := c.CallStatus
+	if len(s) < 1 {
+		s = c.Status
+	}
+	return strings.ToLower(string(s))
+}
@@ -0,0 +1,97 @@
+// Package webhook contains request decoding shared by webhook
+// handlers.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decode fills dst, a struct with json tags, from a GET query, a
+// form POST or a JSON body.
+func Decode(r *http.Request, dst interface{}) error {
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if r.Method == http.MethodPost && ct == "application/json" {
+		return json.NewDecoder(r.Body).Decode(dst)
+	}
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	m := make(map[string]string, len(r.Form))
+	for k := range r.Form {
+		m[k] = r.Form.Get(k)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+// String unmarshals JSON strings, numbers and booleans as a string.
+// Nexmo sends the same field as string or number depending on the
+// request format.
+type String string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *String) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*s = ""
+		return nil
+	}
+	if strings.HasPrefix(string(b), `"`) {
+		var v string
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		*s = String(v)
+		return nil
+	}
+	// numbers are kept verbatim, e.g. large ids and prices.
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	*s = String(fmt.Sprint(v))
+	return nil
+}
+
+// Time parses Nexmo RFC 3339 or "2006-01-02 15:04:05" UTC times,
+// invalid values return zero time.
+func Time(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Seconds parses a whole number of seconds, invalid values return 0.
+func Seconds(s string) time.Duration {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(n) * time.Second
+}
+
+// Float parses a decimal number, invalid values return 0.
+func Float(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// MethodAllowed reports whether r uses GET or POST, the methods
+// Nexmo callbacks can be configured with.
+func MethodAllowed(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodPost
+}
@@ -0,0 +1,400 @@
+package ncco
+
+import (
+	"net/url"
+	"unicode/utf8"
+
+	"github.com/jimmy-go/nexmo/voice"
+)
+
+// LoopForever repeats talk or stream until the call ends.
+const LoopForever = -1
+
+// loop maps Loop field to Nexmo value where 0 means forever and
+// a missing value means once.
+func loop(n int) *int {
+	switch n {
+	case 0:
+		return nil
+	case LoopForever:
+		n = 0
+	}
+	return &n
+}
+
+func validLoop(action string, n int) error {
+	if n < LoopForever {
+		return invalid(action, "loop", "must be LoopForever or positive")
+	}
+	return nil
+}
+
+func validLevel(action string, l float64) error {
+	if l < -1 || l > 1 {
+		return invalid(action, "level", "must be between -1 and 1")
+	}
+	return nil
+}
+
+func validURLs(action, field string, urls []string, required bool) error {
+	if required && len(urls) < 1 {
+		return invalid(action, field, "is required")
+	}
+	for _, s := range urls {
+		u, err := url.Parse(s)
+		if err != nil || len(u.Host) < 1 ||
+			(u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "ws" && u.Scheme != "wss") {
+			return invalid(action, field, "must be absolute URLs")
+		}
+	}
+	return nil
+}
+
+func validMethod(action, field, m string) error {
+	if len(m) > 0 && m != "GET" && m != "POST" {
+		return invalid(action, field, "must be GET or POST")
+	}
+	return nil
+}
+
+// Talk reads text to the call with a synthesized voice.
+type Talk struct {
+	Text string `json:"text"`
+
+	BargeIn bool `json:"bargeIn,omitempty"`
+
+	// Loop times to repeat, zero sends Nexmo default 1.
+	Loop int `json:"loop,omitempty"`
+
+	// Level volume between -1 and 1.
+	Level     float64 `json:"level,omitempty"`
+	VoiceName string  `json:"voiceName,omitempty"`
+}
+
+// ActionName implements Action.
+func (a *Talk) ActionName() string { return "talk" }
+
+// Validate implements Action.
+func (a *Talk) Validate() error {
+	if n := utf8.RuneCountInString(a.Text); n < 1 || n > 1500 {
+		return invalid(a.ActionName(), "text", "must have 1 to 1500 characters")
+	}
+	if err := validLoop(a.ActionName(), a.Loop); err != nil {
+		return err
+	}
+	return validLevel(a.ActionName(), a.Level)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a *Talk) MarshalJSON() ([]byte, error) {
+	type talk Talk
+	return marshalAction(a.ActionName(), struct {
+		talk
+		Loop *int `json:"loop,omitempty"`
+	}{talk(*a), loop(a.Loop)})
+}
+
+// Stream plays audio files to the call.
+type Stream struct {
+	StreamURL []string `json:"streamUrl"`
+	BargeIn   bool     `json:"bargeIn,omitempty"`
+
+	// Loop times to repeat, zero sends Nexmo default 1.
+	Loop int `json:"loop,omitempty"`
+
+	// Level volume between -1 and 1.
+	Level float64 `json:"level,omitempty"`
+}
+
+// ActionName implements Action.
+func (a *Stream) ActionName() string { return "stream" }
+
+// Validate implements Action.
+func (a *Stream) Validate() error {
+	if len(a.StreamURL) != 1 {
+		return invalid(a.ActionName(), "streamUrl", "must have exactly one URL")
+	}
+	if err := validURLs(a.ActionName(), "streamUrl", a.StreamURL, true); err != nil {
+		return err
+	}
+	if err := validLoop(a.ActionName(), a.Loop); err != nil {
+		return err
+	}
+	return validLevel(a.ActionName(), a.Level)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a *Stream) MarshalJSON() ([]byte, error) {
+	type stream Stream
+	return marshalAction(a.ActionName(), struct {
+		stream
+		Loop *int `json:"loop,omitempty"`
+	}{stream(*a), loop(a.Loop)})
+}
+
+// DTMF input settings.
+type DTMF struct {
+	// TimeOut seconds of inactivity before submitting, 0 to 10.
+	TimeOut      int  `json:"timeOut,omitempty"`
+	MaxDigits    int  `json:"maxDigits,omitempty"`
+	SubmitOnHash bool `json:"submitOnHash,omitempty"`
+}
+
+// Speech input settings.
+type Speech struct {
+	UUID         []string `json:"uuid,omitempty"`
+	EndOnSilence float64  `json:"endOnSilence,omitempty"`
+	Language     string   `json:"language,omitempty"`
+	Context      []string `json:"context,omitempty"`
+	StartTimeout int      `json:"startTimeout,omitempty"`
+	MaxDuration  int      `json:"maxDuration,omitempty"`
+}
+
+// Input collects DTMF digits or speech from the call.
+type Input struct {
+	// Type dtmf, speech or both.
+	Type        []string `json:"type,omitempty"`
+	DTMF        *DTMF    `json:"dtmf,omitempty"`
+	Speech      *Speech  `json:"speech,omitempty"`
+	EventURL    []string `json:"eventUrl,omitempty"`
+	EventMethod string   `json:"eventMethod,omitempty"`
+}
+
+// ActionName implements Action.
+func (a *Input) ActionName() string { return "input" }
+
+// Validate implements Action.
+func (a *Input) Validate() error {
+	for _, t := range a.Type {
+		if t != "dtmf" && t != "speech" {
+			return invalid(a.ActionName(), "type", "must be dtmf or speech")
+		}
+	}
+	if d := a.DTMF; d != nil {
+		if d.TimeOut < 0 || d.TimeOut > 10 {
+			return invalid(a.ActionName(), "dtmf.timeOut", "must be between 0 and 10")
+		}
+		if d.MaxDigits != 0 && (d.MaxDigits < 1 || d.MaxDigits > 20) {
+			return invalid(a.ActionName(), "dtmf.maxDigits", "must be between 1 and 20")
+		}
+	}
+	if s := a.Speech; s != nil {
+		if s.EndOnSilence < 0 || s.EndOnSilence > 10 {
+			return invalid(a.ActionName(), "speech.endOnSilence", "must be between 0 and 10")
+		}
+		if s.StartTimeout != 0 && (s.StartTimeout < 1 || s.StartTimeout > 10) {
+			return invalid(a.ActionName(), "speech.startTimeout", "must be between 1 and 10")
+		}
+		if s.MaxDuration != 0 && (s.MaxDuration < 1 || s.MaxDuration > 60) {
+			return invalid(a.ActionName(), "speech.maxDuration", "must be between 1 and 60")
+		}
+	}
+	if err := validURLs(a.ActionName(), "eventUrl", a.EventURL, false); err != nil {
+		return err
+	}
+	return validMethod(a.ActionName(), "eventMethod", a.EventMethod)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a *Input) MarshalJSON() ([]byte, error) {
+	type input Input
+	return marshalAction(a.ActionName(), input(*a))
+}
+
+// Record records the call.
+type Record struct {
+	// Format mp3, wav or ogg.
+	Format       string `json:"format,omitempty"`
+	Split        string `json:"split,omitempty"`
+	Channels     int    `json:"channels,omitempty"`
+	EndOnSilence int    `json:"endOnSilence,omitempty"`
+	EndOnKey     string `json:"endOnKey,omitempty"`
+
+	// TimeOut maximum recording seconds, 3 to 7200.
+	TimeOut     int      `json:"timeOut,omitempty"`
+	BeepStart   bool     `json:"beepStart,omitempty"`
+	EventURL    []string `json:"eventUrl,omitempty"`
+	EventMethod string   `json:"eventMethod,omitempty"`
+}
+
+// ActionName implements Action.
+func (a *Record) ActionName() string { return "record" }
+
+// Validate implements Action.
+func (a *Record) Validate() error {
+	switch a.Format {
+	case "", "mp3", "wav", "ogg":
+	default:
+		return invalid(a.ActionName(), "format", "must be mp3, wav or ogg")
+	}
+	if len(a.Split) > 0 && a.Split != "conversation" {
+		return invalid(a.ActionName(), "split", "must be conversation")
+	}
+	if a.Channels != 0 && (a.Channels < 1 || a.Channels > 32) {
+		return invalid(a.ActionName(), "channels", "must be between 1 and 32")
+	}
+	if a.Channels > 1 && a.Split != "conversation" {
+		return invalid(a.ActionName(), "channels", "requires split conversation")
+	}
+	if a.EndOnSilence != 0 && (a.EndOnSilence < 3 || a.EndOnSilence > 10) {
+		return invalid(a.ActionName(), "endOnSilence", "must be between 3 and 10")
+	}
+	if len(a.EndOnKey) > 0 && (len(a.EndOnKey) != 1 || !containsByte("0123456789*#", a.EndOnKey[0])) {
+		return invalid(a.ActionName(), "endOnKey", "must be a single digit, * or #")
+	}
+	if a.TimeOut != 0 && (a.TimeOut < 3 || a.TimeOut > 7200) {
+		return invalid(a.ActionName(), "timeOut", "must be between 3 and 7200")
+	}
+	if err := validURLs(a.ActionName(), "eventUrl", a.EventURL, false); err != nil {
+		return err
+	}
+	return validMethod(a.ActionName(), "eventMethod", a.EventMethod)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a *Record) MarshalJSON() ([]byte, error) {
+	type record Record
+	return marshalAction(a.ActionName(), record(*a))
+}
+
+// Conversation joins the call to a named conversation.
+type Conversation struct {
+	Name           string   `json:"name"`
+	MusicOnHoldURL []string `json:"musicOnHoldUrl,omitempty"`
+
+	// StartOnEnter nil sends Nexmo default true.
+	StartOnEnter *bool    `json:"startOnEnter,omitempty"`
+	EndOnExit    bool     `json:"endOnExit,omitempty"`
+	Record       bool     `json:"record,omitempty"`
+	CanSpeak     []string `json:"canSpeak,omitempty"`
+	CanHear      []string `json:"canHear,omitempty"`
+}
+
+// ActionName implements Action.
+func (a *Conversation) ActionName() string { return "conversation" }
+
+// Validate implements Action.
+func (a *Conversation) Validate() error {
+	if len(a.Name) < 1 {
+		return invalid(a.ActionName(), "name", "is required")
+	}
+	return validURLs(a.ActionName(), "musicOnHoldUrl", a.MusicOnHoldURL, false)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a *Conversation) MarshalJSON() ([]byte, error) {
+	type conversation Conversation
+	return marshalAction(a.ActionName(), conversation(*a))
+}
+
+// Endpoint connect destination, see voice.Phone, voice.WebSocket
+// and voice.SIP.
+type Endpoint = voice.Endpoint
+
+// Connect connects the call to a phone, websocket or sip endpoint.
+type Connect struct {
+	Endpoint []Endpoint `json:"endpoint"`
+	From     string     `json:"from,omitempty"`
+
+	// EventType synchronous to receive every call event.
+	EventType string `json:"eventType,omitempty"`
+
+	// Timeout seconds ringing before giving up.
+	Timeout int `json:"timeout,omitempty"`
+
+	// Limit maximum call seconds.
+	Limit            int      `json:"limit,omitempty"`
+	MachineDetection string   `json:"machineDetection,omitempty"`
+	EventURL         []string `json:"eventUrl,omitempty"`
+	EventMethod      string   `json:"eventMethod,omitempty"`
+}
+
+// ActionName implements Action.
+func (a *Connect) ActionName() string { return "connect" }
+
+// Validate implements Action.
+func (a *Connect) Validate() error {
+	if len(a.Endpoint) != 1 {
+		return invalid(a.ActionName(), "endpoint", "must have exactly one endpoint")
+	}
+	e := a.Endpoint[0]
+	switch e.Type {
+	case "phone":
+		if len(e.Number) < 1 {
+			return invalid(a.ActionName(), "endpoint.number", "is required for phone endpoints")
+		}
+	case "websocket":
+		if err := validURLs(a.ActionName(), "endpoint.uri", []string{e.URI}, true); err != nil {
+			return err
+		}
+		if len(e.ContentType) < 1 {
+			return invalid(a.ActionName(), "endpoint.content-type", "is required for websocket endpoints")
+		}
+	case "sip":
+		if len(e.URI) < 1 {
+			return invalid(a.ActionName(), "endpoint.uri", "is required for sip endpoints")
+		}
+	default:
+		return invalid(a.ActionName(), "endpoint.type", "must be phone, websocket or sip")
+	}
+	if len(a.EventType) > 0 && a.EventType != "synchronous" {
+		return invalid(a.ActionName(), "eventType", "must be synchronous")
+	}
+	if a.Timeout < 0 {
+		return invalid(a.ActionName(), "timeout", "must be positive")
+	}
+	if a.Limit != 0 && (a.Limit < 1 || a.Limit > 7200) {
+		return invalid(a.ActionName(), "limit", "must be between 1 and 7200")
+	}
+	switch a.MachineDetection {
+	case "", "continue", "hangup":
+	default:
+		return invalid(a.ActionName(), "machineDetection", "must be continue or hangup")
+	}
+	if err := validURLs(a.ActionName(), "eventUrl", a.EventURL, false); err != nil {
+		return err
+	}
+	return validMethod(a.ActionName(), "eventMethod", a.EventMethod)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a *Connect) MarshalJSON() ([]byte, error) {
+	type connect Connect
+	return marshalAction(a.ActionName(), connect(*a))
+}
+
+// Notify sends payload to EventURL.
+type Notify struct {
+	Payload     map[string]interface{} `json:"payload"`
+	EventURL    []string               `json:"eventUrl"`
+	EventMethod string                 `json:"eventMethod,omitempty"`
+}
+
+// ActionName implements Action.
+func (a *Notify) ActionName() string { return "notify" }
+
+// Validate implements Action.
+func (a *Notify) Validate() error {
+	if a.Payload == nil {
+		return invalid(a.ActionName(), "payload", "is required")
+	}
+	if err := validURLs(a.ActionName(), "eventUrl", a.EventURL, true); err != nil {
+		return err
+	}
+	return validMethod(a.ActionName(), "eventMethod", a.EventMethod)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a *Notify) MarshalJSON() ([]byte, error) {
+	type notify Notify
+	return marshalAction(a.ActionName(), notify(*a))
+}
+
+func containsByte(s string, c byte) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+// Package ncco contains a Nexmo Call Control Object builder for
+// call answer URLs.
+//
+//	n := ncco.New().
+//		Talk(ncco.Talk{Text: "Press 1 for sales"}).
+//		Input(ncco.Input{EventURL: []string{"https://example.com/input"}})
+//
+// see: https://docs.nexmo.com/voice/voice-api/ncco-reference
+package ncco
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Action is a single NCCO action.
+type Action interface {
+	// ActionName returns the action name, e.g. talk.
+	ActionName() string
+
+	// Validate checks action fields.
+	Validate() error
+}
+
+// ActionError names the action and field that is not valid.
+type ActionError struct {
+	// Index action position in the NCCO.
+	Index  int
+	Action string
+	Field  string
+	Reason string
+}
+
+// Error implements error interface.
+func (e *ActionError) Error() string {
+	return fmt.Sprintf("nexmo: ncco : action [%d] %s field [%s] %s", e.Index, e.Action, e.Field, e.Reason)
+}
+
+// NCCO list of actions executed in order.
+type NCCO struct {
+	actions []Action
+}
+
+// New returns an empty NCCO.
+func New() *NCCO {
+	return &NCCO{}
+}
+
+// Add appends a.
+func (n *NCCO) Add(a Action) *NCCO {
+	n.actions = append(n.actions, a)
+	return n
+}
+
+// Talk appends a talk action.
+func (n *NCCO) Talk(a Talk) *NCCO {
+	return n.Add(&a)
+}
+
+// Stream appends a stream action.
+func (n *NCCO) Stream(a Stream) *NCCO {
+	return n.Add(&a)
+}
+
+// Input appends an input action.
+func (n *NCCO) Input(a Input) *NCCO {
+	return n.Add(&a)
+}
+
+// Record appends a record action.
+func (n *NCCO) Record(a Record) *NCCO {
+	return n.Add(&a)
+}
+
+// Conversation appends a conversation action.
+func (n *NCCO) Conversation(a Conversation) *NCCO {
+	return n.Add(&a)
+}
+
+// Connect appends a connect action.
+func (n *NCCO) Connect(a Connect) *NCCO {
+	return n.Add(&a)
+}
+
+// Notify appends a notify action.
+func (n *NCCO) Notify(a Notify) *NCCO {
+	return n.Add(&a)
+}
+
+// Actions returns NCCO actions.
+func (n *NCCO) Actions() []Action {
+	return n.actions
+}
+
+// Validate checks every action. It returns an *ActionError.
+func (n *NCCO) Validate() error {
+	if len(n.actions) < 1 {
+		return &ActionError{Index: -1, Field: "actions", Reason: "at least one action is required"}
+	}
+	for i, a := range n.actions {
+		if err := a.Validate(); err != nil {
+			if ae, ok := err.(*ActionError); ok {
+				ae.Index = i
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalJSON validates and marshals NCCO as a JSON array.
+func (n *NCCO) MarshalJSON() ([]byte, error) {
+	if err := n.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(n.actions)
+}
+
+// ServeHTTP writes NCCO as an answer URL response. Invalid NCCO
+// answers 500.
+func (n *NCCO) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, err := n.MarshalJSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+// Bool returns a pointer to b for optional fields.
+func Bool(b bool) *bool {
+	return &b
+}
+
+// marshalAction marshals v adding "action" as first key.
+func marshalAction(name string, v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	head := []byte(`{"action":"` + name + `"`)
+	if len(b) > 2 {
+		head = append(head, ',')
+	}
+	return append(head, b[1:]...), nil
+}
+
+func invalid(action, field, reason string) error {
+	return &ActionError{Action: action, Field: field, Reason: reason}
+}
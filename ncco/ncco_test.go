@@ -0,0 +1,89 @@
+// Package ncco contains tests for ncco package.
+package ncco
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jimmy-go/nexmo/voice"
+)
+
+func TestMarshal(t *testing.T) {
+	n := New().
+		Talk(Talk{Text: "Hello", BargeIn: true, Loop: LoopForever, VoiceName: "Amy"}).
+		Stream(Stream{StreamURL: []string{"https://example.com/a.mp3"}, Loop: 2}).
+		Input(Input{
+			Type:     []string{"dtmf"},
+			DTMF:     &DTMF{MaxDigits: 1, SubmitOnHash: true},
+			EventURL: []string{"https://example.com/input"},
+		}).
+		Record(Record{Format: "mp3", EndOnKey: "#", BeepStart: true}).
+		Conversation(Conversation{Name: "room", StartOnEnter: Bool(false)}).
+		Connect(Connect{
+			Endpoint: []Endpoint{voice.WebSocket("wss://example.com/socket", "audio/l16;rate=16000", nil)},
+			From:     "12015550123",
+		}).
+		Notify(Notify{Payload: map[string]interface{}{"foo": "bar"}, EventURL: []string{"https://example.com/notify"}})
+	b, err := json.Marshal(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"action":"talk","text":"Hello","bargeIn":true,"voiceName":"Amy","loop":0},` +
+		`{"action":"stream","streamUrl":["https://example.com/a.mp3"],"loop":2},` +
+		`{"action":"input","type":["dtmf"],"dtmf":{"maxDigits":1,"submitOnHash":true},"eventUrl":["https://example.com/input"]},` +
+		`{"action":"record","format":"mp3","endOnKey":"#","beepStart":true},` +
+		`{"action":"conversation","name":"room","startOnEnter":false},` +
+		`{"action":"connect","endpoint":[{"type":"websocket","uri":"wss://example.com/socket","content-type":"audio/l16;rate=16000"}],"from":"12015550123"},` +
+		`{"action":"notify","payload":{"foo":"bar"},"eventUrl":["https://example.com/notify"]}]`
+	if string(b) != expected {
+		t.Errorf("expected\n%s\nactual\n%s", expected, b)
+	}
+
+	w := httptest.NewRecorder()
+	n.ServeHTTP(w, httptest.NewRequest("GET", "/answer", nil))
+	if w.Code != 200 || w.Body.String() != expected || w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected response [%d] [%s]", w.Code, w.Body.String())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	table := []struct {
+		NCCO  *NCCO
+		Index int
+		Field string
+	}{
+		{New(), -1, "actions"},
+		{New().Talk(Talk{}), 0, "text"},
+		{New().Talk(Talk{Text: "a"}).Stream(Stream{StreamURL: []string{"a.mp3"}}), 1, "streamUrl"},
+		{New().Input(Input{DTMF: &DTMF{MaxDigits: 21}}), 0, "dtmf.maxDigits"},
+		{New().Input(Input{DTMF: &DTMF{MaxDigits: -1}}), 0, "dtmf.maxDigits"},
+		{New().Input(Input{Speech: &Speech{StartTimeout: -1}}), 0, "speech.startTimeout"},
+		{New().Record(Record{EndOnSilence: 2}), 0, "endOnSilence"},
+		{New().Talk(Talk{Text: strings.Repeat("a", 1501)}), 0, "text"},
+		{New().Record(Record{Channels: 2}), 0, "channels"},
+		{New().Conversation(Conversation{}), 0, "name"},
+		{New().Connect(Connect{Endpoint: []Endpoint{voice.Phone("")}}), 0, "endpoint.number"},
+		{New().Notify(Notify{Payload: map[string]interface{}{}}), 0, "eventUrl"},
+	}
+	// unset fields use Nexmo defaults, text length counts characters.
+	valid := New().
+		Talk(Talk{Text: strings.Repeat("ж", 1500)}).
+		Input(Input{DTMF: &DTMF{}, Speech: &Speech{}}).
+		Record(Record{})
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected err [%v]", err)
+	}
+	for i := range table {
+		x := table[i]
+		err := x.NCCO.Validate()
+		ae, ok := err.(*ActionError)
+		if !ok || ae.Index != x.Index || ae.Field != x.Field {
+			t.Errorf("%d : expected field [%s] actual [%v]", i, x.Field, err)
+		}
+		if _, err := json.Marshal(x.NCCO); err == nil {
+			t.Errorf("%d : expected marshal error", i)
+		}
+	}
+}
@@ -4,19 +4,25 @@
 package nexmo
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/go-querystring/query"
 	"github.com/jimmy-go/nexmo/call"
+	"github.com/jimmy-go/nexmo/phonenumber"
+	"github.com/jimmy-go/nexmo/signature"
 	"github.com/jimmy-go/nexmo/sms"
 	"github.com/jimmy-go/nexmo/text2speech"
+	"github.com/jimmy-go/nexmo/voice"
 )
 
 var (
@@ -30,7 +36,7 @@ var (
 	ErrEmptyResponse = errors.New("nexmo: response is empty")
 
 	// ErrBadRequest is return when http response status
-	// is not 200.
+	// is not 200. Use errors.As with *APIError for details.
 	ErrBadRequest = errors.New("nexmo: bad request")
 
 	// ErrSupportNotFound returned when this package has no
@@ -39,26 +45,50 @@ var (
 )
 
 const (
+	// BaseURLRest Nexmo REST API base URL.
+	BaseURLRest = "https://rest.nexmo.com"
+
+	// BaseURLAPI Nexmo API base URL.
+	BaseURLAPI = "https://api.nexmo.com"
+
 	// EndpointSMS Nexmo API endpoint.
-	EndpointSMS = "https://rest.nexmo.com/sms/json?"
+	EndpointSMS = BaseURLRest + "/sms/json?"
 
 	// EndpointCall Nexmo API endpoint.
-	EndpointCall = "https://rest.nexmo.com/call/json?"
+	EndpointCall = BaseURLRest + "/call/json?"
 
 	// EndpointText2Speech Nexmo API endpoint.
-	EndpointText2Speech = "https://api.nexmo.com/tts/json?"
+	EndpointText2Speech = BaseURLAPI + "/tts/json?"
 )
 
 // Nexmo client
 type Nexmo struct {
-	key    string
-	secret string
-	client *http.Client
+	key          string
+	secret       string
+	client       *http.Client
+	timeout      time.Duration
+	userAgent    string
+	endpoints    map[string]*Support
+	statusErrors bool
+	retryPolicy  *RetryPolicy
+	logger       *log.Logger
+	sigSecret    string
+	sigMethod    signature.Method
+	normalize    bool
+	numberRegion string
+	limiters     map[string]*rateLimiter
+	apiURL       string
+	jwt          *voice.JWTGenerator
 	sync.RWMutex
 }
 
 // New returns a new Nexmo client with timeout.
 func New(key, secret string, timeout time.Duration) (*Nexmo, error) {
+	return NewWithOptions(key, secret, WithTimeout(timeout))
+}
+
+// NewWithOptions returns a new Nexmo client configured with opts.
+func NewWithOptions(key, secret string, opts ...Option) (*Nexmo, error) {
 	if len(key) < 1 {
 		return nil, ErrInvalidKey
 	}
@@ -66,11 +96,25 @@ func New(key, secret string, timeout time.Duration) (*Nexmo, error) {
 		return nil, ErrInvalidSecret
 	}
 	n := &Nexmo{
-		key:    key,
-		secret: secret,
-		client: &http.Client{
-			Timeout: timeout,
-		},
+		key:       key,
+		secret:    secret,
+		client:    &http.Client{},
+		apiURL:    BaseURLAPI,
+		endpoints: make(map[string]*Support, len(supportmap)),
+	}
+	for k, v := range supportmap {
+		sup := *v
+		n.endpoints[k] = &sup
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	if n.timeout > 0 {
+		// copy so a shared client given with WithHTTPClient
+		// is not modified.
+		c := *n.client
+		c.Timeout = n.timeout
+		n.client = &c
 	}
 	return n, nil
 }
@@ -84,7 +128,15 @@ func Must(key, secret string, timeout time.Duration) *Nexmo {
 	return nex
 }
 
-// Support struct
+// SetSMSStatusErrors when enabled makes SMS return sms.StatusErrors
+// if any message part has a failure status.
+func (x *Nexmo) SetSMSStatusErrors(enabled bool) {
+	x.Lock()
+	x.statusErrors = enabled
+	x.Unlock()
+}
+
+// Support struct describes an endpoint.
 type Support struct {
 	DocURL string
 	Method string
@@ -113,11 +165,24 @@ var (
 	}
 )
 
-// do internal client request doer.
-func (x *Nexmo) do(p url.Values, supportType string, dst interface{}) error {
+// do internal client request doer. When ctx is cancelled or its
+// deadline passes the request is aborted and ctx.Err() is returned.
+func (x *Nexmo) do(ctx context.Context, p url.Values, supportType string, dst interface{}) error {
+	if err := x.wait(ctx, supportType); err != nil {
+		return err
+	}
+	// copy config so the lock is not held while the request is
+	// in flight.
 	x.RLock()
-	defer x.RUnlock()
-	resource, ok := supportmap[supportType]
+	sup, ok := x.endpoints[supportType]
+	var resource Support
+	if ok {
+		resource = *sup
+	}
+	key, secret := x.key, x.secret
+	sigSecret, sigMethod := x.sigSecret, x.sigMethod
+	userAgent, logger, client := x.userAgent, x.logger, x.client
+	x.RUnlock()
 	if !ok {
 		return ErrSupportNotFound
 	}
@@ -133,34 +198,113 @@ func (x *Nexmo) do(p url.Values, supportType string, dst interface{}) error {
 		}
 	}
 	// force credentials
-	p.Set("api_key", x.key)
-	p.Set("api_secret", x.secret)
-	uri := resource.URL + p.Encode()
-	req, err := http.NewRequest("GET", uri, nil)
+	p.Set("api_key", key)
+	if len(sigSecret) > 0 && supportType == "sms" {
+		p.Del("api_secret")
+		p.Del("sig")
+		p.Set("timestamp", strconv.FormatInt(time.Now().Unix(), 10))
+		sig, err := signature.Sign(p, sigSecret, sigMethod)
+		if err != nil {
+			return err
+		}
+		p.Set("sig", sig)
+	} else {
+		p.Set("api_secret", secret)
+	}
+	req, err := newRequest(ctx, &resource, p)
 	if err != nil {
 		return err
 	}
-	resp, err := x.client.Do(req)
+	if len(userAgent) > 0 {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	if logger != nil {
+		logger.Printf("Nexmo : do : %s %s params [%s]", req.Method, req.URL.Path, redact(p))
+	}
+	resp, err := client.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		return err
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	if logger != nil {
+		logger.Printf("Nexmo : do : %s status [%d] body [%s]", req.URL.Path, resp.StatusCode, body)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		x.feedback(supportType, true)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return ErrBadRequest
+		apiErr := newAPIError(supportType, resp.StatusCode, body, nil)
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		return apiErr
+	}
+	err = json.Unmarshal(body, dst)
+	if err != nil {
+		return newAPIError(supportType, resp.StatusCode, body, err)
+	}
+	return nil
+}
+
+// newRequest builds the HTTP request for resource. POST requests
+// send params as a form body so credentials never appear in the URL.
+func newRequest(ctx context.Context, resource *Support, p url.Values) (*http.Request, error) {
+	uri := strings.TrimSuffix(resource.URL, "?")
+	if resource.Method != http.MethodPost {
+		return http.NewRequestWithContext(ctx, resource.Method, uri+"?"+p.Encode(), nil)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, strings.NewReader(p.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+// redactKeys params never written to logs.
+var redactKeys = []string{"api_secret", "sig"}
+
+// redact returns encoded p with secrets replaced.
+func redact(p url.Values) string {
+	c := url.Values{}
+	for k, v := range p {
+		c[k] = v
+	}
+	for _, k := range redactKeys {
+		if _, ok := c[k]; ok {
+			c.Set(k, "REDACTED")
+		}
 	}
-	err = json.NewDecoder(resp.Body).Decode(dst)
+	return c.Encode()
+}
+
+// normalizeNumbers rewrites to, and from when it is a phone number,
+// in digits only international format. Senders that do not parse,
+// e.g. short codes, are left unchanged. See WithNumberNormalizer.
+func (x *Nexmo) normalizeNumbers(to, from *string) error {
+	n, err := phonenumber.Normalize(*to, x.numberRegion)
 	if err != nil {
-		buf := bytes.NewBuffer([]byte{})
-		_, _ = buf.ReadFrom(resp.Body)
-		log.Printf("Nexmo : do : err body [%v]", buf.String())
 		return err
 	}
+	*to = n
+	if n, err := phonenumber.Normalize(*from, x.numberRegion); err == nil {
+		*from = n
+	}
 	return nil
 }
 
 // NewSMS returns a new SMS request only with required fields.
+// Type is set to unicode when text can not be sent as GSM-7.
 // see: https://docs.nexmo.com/messaging/sms-api/api-reference#request
 func NewSMS(to, from, text string) *sms.Request {
 	req := &sms.Request{
@@ -168,6 +312,9 @@ func NewSMS(to, from, text string) *sms.Request {
 		From: from,
 		Text: text,
 	}
+	if !sms.IsGSM(text) {
+		req.Type = sms.TypeUnicode
+	}
 	return req
 }
 
@@ -176,17 +323,79 @@ func NewSMS(to, from, text string) *sms.Request {
 //
 // see: https://docs.nexmo.com/messaging/sms-api
 func (x *Nexmo) SMS(r *sms.Request) (*sms.Response, error) {
+	return x.SMSContext(context.Background(), r)
+}
+
+// SMSContext is like SMS but the request is bound to ctx.
+func (x *Nexmo) SMSContext(ctx context.Context, r *sms.Request) (*sms.Response, error) {
+	if x.normalize {
+		c := *r
+		if err := x.normalizeNumbers(&c.To, &c.From); err != nil {
+			return nil, err
+		}
+		r = &c
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
 	v, err := query.Values(r)
 	if err != nil {
 		return nil, err
 	}
 	var res *sms.Response
-	err = x.do(v, "sms", &res)
-	if err != nil {
+	err = x.retry(ctx, "sms", func() error {
+		res = nil
+		err := x.do(ctx, v, "sms", &res)
+		if err != nil {
+			return err
+		}
+		if len(res.Messages) < 1 {
+			return ErrEmptyResponse
+		}
+		throttled := res.Throttled()
+		x.feedback("sms", throttled)
+		// only a fully rejected response is worth a retry, never
+		// resend when some part was already accepted.
+		errs, ok := res.Err().(sms.StatusErrors)
+		if ok && len(errs) == len(res.Messages) {
+			if throttled {
+				return &throttledError{errs}
+			}
+			return errs
+		}
+		return nil
+	})
+	var statusErrs sms.StatusErrors
+	if err != nil && !errors.As(err, &statusErrs) {
 		return res, err
 	}
-	if len(res.Messages) < 1 {
-		return res, ErrEmptyResponse
+	x.RLock()
+	strict := x.statusErrors
+	x.RUnlock()
+	if strict {
+		return res, res.Err()
+	}
+	return res, nil
+}
+
+// SMSMultipart sends parts in order, e.g. the result of
+// sms.SplitText. It stops at the first failed part.
+func (x *Nexmo) SMSMultipart(parts []*sms.Request) ([]*sms.Response, error) {
+	return x.SMSMultipartContext(context.Background(), parts)
+}
+
+// SMSMultipartContext is like SMSMultipart but requests are bound
+// to ctx.
+func (x *Nexmo) SMSMultipartContext(ctx context.Context, parts []*sms.Request) ([]*sms.Response, error) {
+	res := make([]*sms.Response, 0, len(parts))
+	for i := range parts {
+		r, err := x.SMSContext(ctx, parts[i])
+		if r != nil {
+			res = append(res, r)
+		}
+		if err != nil {
+			return res, err
+		}
 	}
 	return res, nil
 }
@@ -204,12 +413,34 @@ func NewCall(to, answerURL string) *call.Request {
 // Call You use Call API to make outbound calls from Nexmo
 // virtual numbers to other phone numbers.
 func (x *Nexmo) Call(r *call.Request) (*call.Response, error) {
+	return x.CallContext(context.Background(), r)
+}
+
+// CallContext is like Call but the request is bound to ctx.
+func (x *Nexmo) CallContext(ctx context.Context, r *call.Request) (*call.Response, error) {
+	if x.normalize {
+		c := *r
+		if err := x.normalizeNumbers(&c.To, &c.From); err != nil {
+			return nil, err
+		}
+		r = &c
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
 	v, err := query.Values(r)
 	if err != nil {
 		return nil, err
 	}
 	var res *call.Response
-	err = x.do(v, "call", &res)
+	err = x.retry(ctx, "call", func() error {
+		res = nil
+		err := x.do(ctx, v, "call", &res)
+		if err == nil {
+			x.feedback("call", res != nil && res.Status == call.StatusThrottled)
+		}
+		return err
+	})
 	if err != nil {
 		return res, err
 	}
@@ -234,12 +465,35 @@ func NewText2Speech(to, from, text, lang, voice string) *text2speech.Request {
 // Text2Speech You use Text-To-Speech API to send
 // synthesized speech or recorded sound files to a phone number
 func (x *Nexmo) Text2Speech(r *text2speech.Request) (*text2speech.Response, error) {
+	return x.Text2SpeechContext(context.Background(), r)
+}
+
+// Text2SpeechContext is like Text2Speech but the request is
+// bound to ctx.
+func (x *Nexmo) Text2SpeechContext(ctx context.Context, r *text2speech.Request) (*text2speech.Response, error) {
+	if x.normalize {
+		c := *r
+		if err := x.normalizeNumbers(&c.To, &c.From); err != nil {
+			return nil, err
+		}
+		r = &c
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
 	v, err := query.Values(r)
 	if err != nil {
 		return nil, err
 	}
 	var res *text2speech.Response
-	err = x.do(v, "text2speech", &res)
+	err = x.retry(ctx, "text2speech", func() error {
+		res = nil
+		err := x.do(ctx, v, "text2speech", &res)
+		if err == nil {
+			x.feedback("text2speech", res != nil && res.Status == text2speech.StatusThrottled)
+		}
+		return err
+	})
 	if err != nil {
 		return res, err
 	}
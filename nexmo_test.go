@@ -2,8 +2,28 @@
 package nexmo
 
 import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/jimmy-go/nexmo/call"
+	"github.com/jimmy-go/nexmo/nexmotest"
+	"github.com/jimmy-go/nexmo/phonenumber"
+	"github.com/jimmy-go/nexmo/signature"
+	"github.com/jimmy-go/nexmo/sms"
+	"github.com/jimmy-go/nexmo/text2speech"
+	"github.com/jimmy-go/nexmo/voice"
 )
 
 type T struct {
@@ -23,6 +43,21 @@ type Input struct {
 	AnswerURL string
 }
 
+// newTestClient returns a client pointing to a local server running h.
+func newTestClient(t *testing.T, h http.HandlerFunc, opts ...Option) *Nexmo {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	opts = append([]Option{
+		WithBaseURL(srv.URL, srv.URL),
+		WithTimeout(time.Second),
+	}, opts...)
+	client, err := NewWithOptions("123", "456", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
 func TestNew(t *testing.T) {
 	table := []T{
 		T{
@@ -55,12 +90,17 @@ func TestNew(t *testing.T) {
 }
 
 func TestTableSMS(t *testing.T) {
+	srv := nexmotest.NewServer()
+	defer srv.Close()
 	table := []T{
 		T{
 			Input: Input{
 				Key:     "123",
 				Secret:  "123",
 				Timeout: time.Second * 10,
+				To:      "5215522334455",
+				From:    "NexmoTest",
+				Text:    "Hello world!",
 			},
 			Expected: nil,
 		},
@@ -69,13 +109,17 @@ func TestTableSMS(t *testing.T) {
 				Key:     "123",
 				Secret:  "123",
 				Timeout: time.Second * 10,
+				To:      "5215522334455",
+				From:    "NexmoTest",
+				Text:    "Hello world!",
 			},
 			Expected: nil,
 		},
 	}
 	for i := range table {
 		x := table[i]
-		client, err := New(x.Input.Key, x.Input.Secret, x.Input.Timeout)
+		client, err := NewWithOptions(x.Input.Key, x.Input.Secret,
+			WithTimeout(x.Input.Timeout), WithBaseURL(srv.URL, srv.URL))
 		if err != nil {
 			t.Logf("new : err [%v]", err)
 			t.Fail()
@@ -93,12 +137,18 @@ func TestTableSMS(t *testing.T) {
 
 // TODO; review, for now sms method is priority.
 func TestTableText2Speech(t *testing.T) {
+	srv := nexmotest.NewServer()
+	defer srv.Close()
 	table := []T{
 		T{
 			Input: Input{
 				Key:     "123",
 				Secret:  "123",
 				Timeout: time.Second * 10,
+				To:      "5215522334455",
+				Text:    "Hello world!",
+				Lang:    "en-us",
+				Voice:   "female",
 			},
 			// Expected: ErrBadRequest,
 			Expected: nil,
@@ -108,6 +158,10 @@ func TestTableText2Speech(t *testing.T) {
 				Key:     "123",
 				Secret:  "123",
 				Timeout: time.Second * 10,
+				To:      "5215522334455",
+				Text:    "Hello world!",
+				Lang:    "en-us",
+				Voice:   "female",
 			},
 			// Expected: ErrBadRequest,
 			Expected: nil,
@@ -115,7 +169,8 @@ func TestTableText2Speech(t *testing.T) {
 	}
 	for i := range table {
 		x := table[i]
-		client, err := New(x.Input.Key, x.Input.Secret, x.Input.Timeout)
+		client, err := NewWithOptions(x.Input.Key, x.Input.Secret,
+			WithTimeout(x.Input.Timeout), WithBaseURL(srv.URL, srv.URL))
 		if err != nil {
 			t.Errorf("new : err [%v]", err)
 			continue
@@ -130,6 +185,8 @@ func TestTableText2Speech(t *testing.T) {
 }
 
 func TestTableCall(t *testing.T) {
+	srv := nexmotest.NewServer()
+	defer srv.Close()
 	table := []T{
 		T{
 			Input: Input{
@@ -154,7 +211,8 @@ func TestTableCall(t *testing.T) {
 	}
 	for i := range table {
 		x := table[i]
-		client, err := New(x.Input.Key, x.Input.Secret, x.Input.Timeout)
+		client, err := NewWithOptions(x.Input.Key, x.Input.Secret,
+			WithTimeout(x.Input.Timeout), WithBaseURL(srv.URL, srv.URL))
 		if err != nil {
 			t.Logf("new : err [%v]", err)
 			t.Fail()
@@ -169,3 +227,787 @@ func TestTableCall(t *testing.T) {
 		}
 	}
 }
+
+func TestContextCancel(t *testing.T) {
+	client := Must("123", "456", time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.SMSContext(ctx, NewSMS("5215522334455", "NexmoTest", "Hello"))
+	if err != context.Canceled {
+		t.Errorf("sms : expected [%v] actual [%v]", context.Canceled, err)
+	}
+	_, err = client.CallContext(ctx, NewCall("5215522334455", "http://localhost/somexml.xml"))
+	if err != context.Canceled {
+		t.Errorf("call : expected [%v] actual [%v]", context.Canceled, err)
+	}
+	_, err = client.Text2SpeechContext(ctx, NewText2Speech("5215522334455", "12015550123", "Hello", "en-us", "female"))
+	if err != context.Canceled {
+		t.Errorf("text2speech : expected [%v] actual [%v]", context.Canceled, err)
+	}
+}
+
+func TestInFlightUnlocked(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte(`{"messages":[{"status":"0"}]}`))
+	})
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.SMS(NewSMS("5215522334455", "NexmoTest", "Hello"))
+		done <- err
+	}()
+	<-started
+	// setters must not wait for the request in flight.
+	set := make(chan struct{})
+	go func() {
+		client.SetSMSStatusErrors(true)
+		close(set)
+	}()
+	select {
+	case <-set:
+	case <-time.After(500 * time.Millisecond):
+		t.Error("expected setter to not block while request is in flight")
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("expected [%v] actual [%v]", nil, err)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	table := []struct {
+		Status    int
+		Body      string
+		ErrorText string
+		Bad       bool
+	}{
+		{http.StatusUnauthorized, `{"error-text":"Bad Credentials"}`, "Bad Credentials", true},
+		{http.StatusInternalServerError, `{"error_text":"Internal"}`, "Internal", true},
+		{http.StatusOK, `not json`, "", false},
+	}
+	for i := range table {
+		x := table[i]
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(x.Status)
+			_, _ = w.Write([]byte(x.Body))
+		})
+		_, err := client.Call(NewCall("5215522334455", "http://localhost/somexml.xml"))
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("expected APIError actual [%v]", err)
+			continue
+		}
+		if errors.Is(err, ErrBadRequest) != x.Bad {
+			t.Errorf("expected bad request [%v] actual [%v]", x.Bad, err)
+		}
+		if apiErr.StatusCode != x.Status || apiErr.Endpoint != "call" ||
+			apiErr.ErrorText != x.ErrorText || string(apiErr.Body) != x.Body {
+			t.Errorf("unexpected error [%#v]", apiErr)
+		}
+	}
+}
+
+func TestSMSStatusErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"message-count":"3","messages":[
+			{"status":"0","to":"5215522334455"},
+			{"status":"7","to":"5215522334455","error-text":"Handset Busy"},
+			{"status":"9","to":"5215522334455","error-text":"Illegal Number"}]}`))
+	})
+	msg := NewSMS("5215522334455", "NexmoTest", "Hello")
+	_, err := client.SMS(msg)
+	if err != nil {
+		t.Fatalf("expected [nil] actual [%v]", err)
+	}
+	client.SetSMSStatusErrors(true)
+	_, err = client.SMS(msg)
+	var errs sms.StatusErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected 2 status errors actual [%v]", err)
+	}
+	if !errs[0].Temporary() || errs[0].Index != 1 || errs[0].ErrorText != "Handset Busy" {
+		t.Errorf("unexpected error [%#v]", errs[0])
+	}
+	if !errs[1].Permanent() || errs[1].Index != 2 {
+		t.Errorf("unexpected error [%#v]", errs[1])
+	}
+	var se *sms.StatusError
+	if !errors.As(err, &se) || se.Code != sms.StatusHandsetBusy {
+		t.Errorf("expected first status error actual [%v]", se)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	table := []struct {
+		Responses  []string
+		Status     []int
+		RetryAfter string
+		Attempts   int32
+		MinDelay   time.Duration
+		Err        bool
+	}{
+		// 503 then success.
+		{[]string{`{}`, `{"call-id":"1","status":0}`}, []int{503, 200}, "", 2, 0, false},
+		// 429 with Retry-After waits longer than backoff.
+		{[]string{`{}`, `{"call-id":"1","status":0}`}, []int{429, 200}, "1", 2, time.Second, false},
+		// 400 is never retried.
+		{[]string{`{}`}, []int{400}, "", 1, 0, true},
+		// exhaust attempts.
+		{[]string{`{}`, `{}`, `{}`}, []int{500, 500, 500}, "", 3, 0, true},
+	}
+	for i := range table {
+		x := table[i]
+		var n int32
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			c := atomic.AddInt32(&n, 1) - 1
+			if len(x.RetryAfter) > 0 {
+				w.Header().Set("Retry-After", x.RetryAfter)
+			}
+			w.WriteHeader(x.Status[c])
+			_, _ = w.Write([]byte(x.Responses[c]))
+		})
+		client.SetRetryPolicy(&RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    5 * time.Millisecond,
+		})
+		start := time.Now()
+		_, err := client.Call(NewCall("5215522334455", "http://localhost/somexml.xml"))
+		if (err != nil) != x.Err {
+			t.Errorf("%d : expected error [%v] actual [%v]", i, x.Err, err)
+		}
+		if atomic.LoadInt32(&n) != x.Attempts {
+			t.Errorf("%d : expected attempts [%d] actual [%d]", i, x.Attempts, n)
+		}
+		if elapsed := time.Since(start); elapsed < x.MinDelay {
+			t.Errorf("%d : expected delay [%v] actual [%v]", i, x.MinDelay, elapsed)
+		}
+	}
+}
+
+func TestRetrySMS(t *testing.T) {
+	table := []struct {
+		First    string
+		Attempts int32
+	}{
+		// every part failed temporary, retry.
+		{`{"messages":[{"status":"7"},{"status":"8"}]}`, 2},
+		// throttled, retry.
+		{`{"messages":[{"status":"1","error-text":"Throughput Rate Exceeded"}]}`, 2},
+		// partially accepted, never resend.
+		{`{"messages":[{"status":"0"},{"status":"7"}]}`, 1},
+		// permanent failure.
+		{`{"messages":[{"status":"9"}]}`, 1},
+	}
+	for i := range table {
+		x := table[i]
+		var n int32
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&n, 1) == 1 {
+				_, _ = w.Write([]byte(x.First))
+				return
+			}
+			_, _ = w.Write([]byte(`{"messages":[{"status":"0"}]}`))
+		})
+		client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+		_, err := client.SMS(NewSMS("5215522334455", "NexmoTest", "Hello"))
+		if err != nil {
+			t.Errorf("%d : expected [nil] actual [%v]", i, err)
+		}
+		if atomic.LoadInt32(&n) != x.Attempts {
+			t.Errorf("%d : expected attempts [%d] actual [%d]", i, x.Attempts, n)
+		}
+	}
+}
+
+func TestRetryDeadline(t *testing.T) {
+	var n int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&n, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := client.CallContext(ctx, NewCall("5215522334455", "http://localhost/somexml.xml"))
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("expected [%v] actual [%v]", ErrBadRequest, err)
+	}
+	if atomic.LoadInt32(&n) != 1 {
+		t.Errorf("expected attempts [1] actual [%d]", n)
+	}
+
+	// Retry-After past the deadline returns the last error.
+	n = 0
+	client = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&n, 1)
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond})
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = client.CallContext(ctx, NewCall("5215522334455", "http://localhost/somexml.xml"))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.RetryAfter != 5*time.Second {
+		t.Errorf("expected 429 error actual [%v]", err)
+	}
+	if atomic.LoadInt32(&n) != 1 || ctx.Err() != nil {
+		t.Errorf("expected attempts [1] before deadline actual [%d] [%v]", n, ctx.Err())
+	}
+}
+
+func TestOptions(t *testing.T) {
+	var paths []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if ua := r.Header.Get("User-Agent"); ua != "nexmo-test" {
+			t.Errorf("expected user agent [nexmo-test] actual [%s]", ua)
+		}
+		_, _ = w.Write([]byte(`{"messages":[{"status":"0"}]}`))
+	}, WithUserAgent("nexmo-test"))
+	_, _ = client.SMS(NewSMS("5215522334455", "NexmoTest", "Hello"))
+	_, _ = client.Call(NewCall("5215522334455", "http://localhost/somexml.xml"))
+	_, _ = client.Text2Speech(NewText2Speech("5215522334455", "12015550123", "Hello", "en-us", "female"))
+	expected := []string{"/sms/json", "/call/json", "/tts/json"}
+	if len(paths) != len(expected) {
+		t.Fatalf("expected paths [%v] actual [%v]", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("expected path [%s] actual [%s]", expected[i], paths[i])
+		}
+	}
+	if supportmap["sms"].URL != EndpointSMS {
+		t.Errorf("global endpoint modified [%s]", supportmap["sms"].URL)
+	}
+
+	shared := &http.Client{}
+	_, err := NewWithOptions("123", "456", WithHTTPClient(shared), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shared.Timeout != 0 {
+		t.Errorf("shared client modified")
+	}
+}
+
+func TestPOSTCredentials(t *testing.T) {
+	var buf bytes.Buffer
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected method [POST] actual [%s]", r.Method)
+		}
+		if len(r.URL.RawQuery) > 0 {
+			t.Errorf("expected empty query actual [%s]", r.URL.RawQuery)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("unexpected content type [%s]", ct)
+		}
+		if r.PostFormValue("api_key") != "123" || r.PostFormValue("api_secret") != "456" {
+			t.Errorf("missing credentials [%v]", r.PostForm)
+		}
+		if r.PostFormValue("text") != "Hello" {
+			t.Errorf("expected text [Hello] actual [%s]", r.PostFormValue("text"))
+		}
+		_, _ = w.Write([]byte(`{"messages":[{"status":"0"}]}`))
+	}, WithLogger(log.New(&buf, "", 0)))
+	_, err := client.SMS(NewSMS("5215522334455", "NexmoTest", "Hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "456") || !strings.Contains(buf.String(), "REDACTED") {
+		t.Errorf("log not redacted [%s]", buf.String())
+	}
+}
+
+func TestSignedSMS(t *testing.T) {
+	methods := []signature.Method{signature.MD5Hash, signature.SHA256, signature.SHA512}
+	for _, m := range methods {
+		method := m
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseForm(); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := r.PostForm["api_secret"]; ok {
+				t.Errorf("%s : api_secret sent", method)
+			}
+			err := signature.Verify(r.PostForm, "sigsecret", method, time.Minute, time.Now())
+			if err != nil {
+				t.Errorf("%s : verify : err [%v]", method, err)
+			}
+			_, _ = w.Write([]byte(`{"messages":[{"status":"0"}]}`))
+		}, WithSignatureSecret("sigsecret", method))
+		_, err := client.SMS(NewSMS("5215522334455", "NexmoTest", "Hello & bye = ok"))
+		if err != nil {
+			t.Errorf("%s : err [%v]", method, err)
+		}
+	}
+}
+
+func TestSMSMultipart(t *testing.T) {
+	var udh []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("type") != sms.TypeBinary || r.PostFormValue("data-coding") != sms.DataCodingGSM {
+			t.Errorf("unexpected part [%v]", r.PostForm)
+		}
+		udh = append(udh, r.PostFormValue("udh"))
+		_, _ = w.Write([]byte(`{"messages":[{"status":"0"}]}`))
+	})
+	parts, err := sms.SplitText(NewSMS("5215522334455", "NexmoTest", strings.Repeat("a", 400)), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.SMSMultipart(parts)
+	if err != nil || len(res) != 3 {
+		t.Fatalf("expected 3 responses actual [%d] [%v]", len(res), err)
+	}
+	for i := range udh {
+		if udh[i] != parts[i].UDH {
+			t.Errorf("expected udh [%s] actual [%s]", parts[i].UDH, udh[i])
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("invalid request sent [%s]", r.URL.Path)
+	})
+	table := []struct {
+		Send  func() error
+		Field string
+	}{
+		{func() error {
+			_, err := client.SMS(NewSMS("55 2233", "NexmoTest", "Hello"))
+			return err
+		}, "To"},
+		{func() error {
+			_, err := client.SMS(NewSMS("5215522334455", "NexmoTestLongSender", "Hello"))
+			return err
+		}, "From"},
+		{func() error {
+			msg := NewSMS("5215522334455", "NexmoTest", "Hello")
+			msg.Validity = "100"
+			_, err := client.SMS(msg)
+			return err
+		}, "Validity"},
+		{func() error {
+			_, err := client.SMS(NewSMS("5215522334455", "NexmoTest", ""))
+			return err
+		}, "Text"},
+		{func() error {
+			_, err := client.Call(NewCall("5215522334455", "/answer.xml"))
+			return err
+		}, "AnswerURL"},
+		{func() error {
+			msg := NewCall("5215522334455", "http://localhost/answer.xml")
+			msg.StatusMethod = "PUT"
+			_, err := client.Call(msg)
+			return err
+		}, "StatusMethod"},
+		{func() error {
+			msg := NewText2Speech("5215522334455", "", "Hello", "en-us", "female")
+			msg.Repeat = 11
+			_, err := client.Text2Speech(msg)
+			return err
+		}, "Repeat"},
+		{func() error {
+			msg := NewText2Speech("5215522334455", "", "Hello", "en-us", "female")
+			msg.Repeat = -1
+			_, err := client.Text2Speech(msg)
+			return err
+		}, "Repeat"},
+	}
+	for i := range table {
+		x := table[i]
+		err := x.Send()
+		var field string
+		switch e := err.(type) {
+		case *sms.FieldError:
+			field = e.Field
+		case *call.FieldError:
+			field = e.Field
+		case *text2speech.FieldError:
+			field = e.Field
+		}
+		if field != x.Field {
+			t.Errorf("%d : expected field [%s] actual [%v]", i, x.Field, err)
+		}
+	}
+}
+
+func TestNumberNormalizer(t *testing.T) {
+	var to, from string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		to, from = r.PostFormValue("to"), r.PostFormValue("from")
+		_, _ = w.Write([]byte(`{"messages":[{"status":"0"}]}`))
+	}, WithNumberNormalizer("MX"))
+	msg := NewSMS("+52 1 55 2233 4455", "(55) 1234-5678", "Hello")
+	_, err := client.SMS(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if to != "5215522334455" || from != "525512345678" {
+		t.Errorf("unexpected to [%s] from [%s]", to, from)
+	}
+	msg.From = "NexmoTest"
+	_, err = client.SMS(msg)
+	if err != nil || from != "NexmoTest" {
+		t.Errorf("unexpected from [%s] [%v]", from, err)
+	}
+	if msg.To != "+52 1 55 2233 4455" {
+		t.Errorf("request modified [%s]", msg.To)
+	}
+	table := []struct {
+		To       string
+		From     string
+		Expected string
+		Sender   string
+	}{
+		{"5215522334455", "12345", "5215522334455", "12345"},
+		{"447700900123", "5215522334455", "447700900123", "5215522334455"},
+		{"55 2233 4455", "NexmoTest", "525522334455", "NexmoTest"},
+	}
+	for i, x := range table {
+		_, err = client.SMS(NewSMS(x.To, x.From, "Hello"))
+		if err != nil || to != x.Expected || from != x.Sender {
+			t.Errorf("%d : expected [%s] [%s] actual [%s] [%s] [%v]", i, x.Expected, x.Sender, to, from, err)
+		}
+	}
+	msg.To = "555-1234"
+	_, err = client.SMS(msg)
+	if err != phonenumber.ErrInvalidLength {
+		t.Errorf("expected [%v] actual [%v]", phonenumber.ErrInvalidLength, err)
+	}
+}
+
+func TestBulkSender(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		status := "0"
+		if r.PostFormValue("to") == "5215500000000" {
+			status = "9"
+		}
+		// balance reaches 0 on the first response.
+		balance := "10.00"
+		if atomic.AddInt32(&calls, 1) == 1 {
+			balance = "0.00"
+		}
+		_, _ = w.Write([]byte(`{"messages":[{"status":"` + status +
+			`","message-price":"0.05","remaining-balance":"` + balance + `"}]}`))
+	})
+	reqs := make([]*sms.Request, 20)
+	for i := range reqs {
+		reqs[i] = NewSMS("5215522334455", "NexmoTest", "Hello")
+	}
+	reqs[3].To = "5215500000000"
+	b := NewBulkSender(client, 4, 200)
+	seen := make(map[int]bool)
+	for res := range b.SendAll(context.Background(), reqs) {
+		seen[res.Index] = true
+		if (res.Err != nil) != (res.Index == 3) {
+			t.Errorf("%d : unexpected err [%v]", res.Index, res.Err)
+		}
+	}
+	if len(seen) != len(reqs) {
+		t.Errorf("expected results [%d] actual [%d]", len(reqs), len(seen))
+	}
+	stats := b.Stats()
+	if stats.Sent != 19 || stats.Failed != 1 || stats.RemainingBalance != 0 || !stats.BalanceReported ||
+		stats.TotalPrice < 0.99 || stats.TotalPrice > 1.01 {
+		t.Errorf("unexpected stats [%+v]", stats)
+	}
+
+	// the limiter takes a token per message part, 5 texts of 2
+	// parts at 10 per second wait 0.9 seconds on a fake clock.
+	b = NewBulkSender(client, 1, 10)
+	now := time.Now()
+	b.limiter.last = now
+	b.limiter.now = func() time.Time { return now }
+	b.limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		now = now.Add(d)
+		return nil
+	}
+	start := now
+	long := make([]*sms.Request, 5)
+	for i := range long {
+		long[i] = NewSMS("5215522334455", "NexmoTest", strings.Repeat("a", 200))
+	}
+	for range b.SendAll(context.Background(), long) {
+	}
+	if d := now.Sub(start); d < 890*time.Millisecond || d > 910*time.Millisecond {
+		t.Errorf("expected wait [900ms] actual [%v]", d)
+	}
+
+	// cancelled sends stop early and close results.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n := 0
+	for range NewBulkSender(client, 2, 1).SendAll(ctx, reqs) {
+		n++
+	}
+	if n > 2 {
+		t.Errorf("expected early stop actual [%d] results", n)
+	}
+}
+
+func TestBulkSenderCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"messages":[{"status":"0"}]}`))
+	})
+	reqs := make([]*sms.Request, 10)
+	for i := range reqs {
+		reqs[i] = NewSMS("5215522334455", "NexmoTest", "Hello")
+	}
+	b := NewBulkSender(client, 4, 0)
+	n := 0
+	for range b.SendAll(ctx, reqs) {
+		n++
+		if n == 3 {
+			cancel()
+		}
+	}
+	if stats := b.Stats(); stats.Sent+stats.Failed != n {
+		t.Errorf("expected stats to count [%d] results actual [%+v]", n, stats)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	var throttle int32 = 1
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&throttle) == 1 {
+			_, _ = w.Write([]byte(`{"messages":[{"status":"1","error-text":"Throughput Rate Exceeded"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"messages":[{"status":"0"}]}`))
+	}, WithRateLimit("sms", RateLimit{PerSecond: 100, MinPerSecond: 20}))
+	msg := NewSMS("5215522334455", "NexmoTest", "Hello")
+	if client.Rate("sms") != 100 || client.Rate("call") != 0 {
+		t.Fatalf("unexpected rates [%v] [%v]", client.Rate("sms"), client.Rate("call"))
+	}
+	for i := 0; i < 3; i++ {
+		_, _ = client.SMS(msg)
+	}
+	if client.Rate("sms") != 20 {
+		t.Errorf("expected rate [20] actual [%v]", client.Rate("sms"))
+	}
+	atomic.StoreInt32(&throttle, 0)
+	_, _ = client.SMS(msg)
+	if rate := client.Rate("sms"); rate <= 20 || rate > 100 {
+		t.Errorf("expected recovering rate actual [%v]", rate)
+	}
+
+	// fail fast when ctx deadline is before next token.
+	_, _ = client.SMS(msg)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err := client.SMSContext(ctx, msg)
+	if err != ErrRateLimited {
+		t.Errorf("expected [%v] actual [%v]", ErrRateLimited, err)
+	}
+
+	// call and text2speech throttle on response status 1.
+	voiceClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"call-id":"","status":"1","error-text":"Throttled"}`))
+	}, WithRateLimit("call", RateLimit{PerSecond: 100, MinPerSecond: 20}),
+		WithRateLimit("text2speech", RateLimit{PerSecond: 100, MinPerSecond: 20}))
+	for i := 0; i < 3; i++ {
+		_, _ = voiceClient.Call(NewCall("5215522334455", "http://localhost/somexml.xml"))
+		_, _ = voiceClient.Text2Speech(NewText2Speech("5215522334455", "12015550123", "Hello", "en-us", "female"))
+	}
+	if voiceClient.Rate("call") != 20 || voiceClient.Rate("text2speech") != 20 {
+		t.Errorf("expected rates [20] [20] actual [%v] [%v]", voiceClient.Rate("call"), voiceClient.Rate("text2speech"))
+	}
+
+	// concurrent feedback must not lose updates.
+	l := newRateLimiter(RateLimit{PerSecond: 1024, MinPerSecond: 1})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.throttled()
+		}()
+	}
+	wg.Wait()
+	if l.bucket.Rate() != 1 {
+		t.Errorf("expected rate [1] actual [%v]", l.bucket.Rate())
+	}
+}
+
+func TestFakeServer(t *testing.T) {
+	srv := nexmotest.NewServer()
+	defer srv.Close()
+	client, err := NewWithOptions("123", "456", WithBaseURL(srv.URL, srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.SetSMS("5215500000000", nexmotest.SMSResult{Status: sms.StatusIllegalNumber, ErrorText: "Illegal Number"})
+	srv.SetSMS("5215522334455", nexmotest.SMSResult{
+		MessagePrice:   "0.05",
+		ReceiptStatus:  "failed",
+		ReceiptErrCode: sms.StatusHandsetBusy,
+	})
+	srv.SetCall("5215511111111", nexmotest.CallResult{HTTPStatus: http.StatusInternalServerError})
+
+	res, err := client.SMS(NewSMS("5215522334455", "NexmoTest", strings.Repeat("a", 200)))
+	if err != nil || len(res.Messages) != 2 || res.Messages[0].MessagePrice != "0.05" {
+		t.Fatalf("unexpected sms response [%v] [%v]", res, err)
+	}
+	res, err = client.SMS(NewSMS("5215500000000", "NexmoTest", "Hello"))
+	if err != nil || res.Messages[0].Status != sms.StatusIllegalNumber {
+		t.Errorf("unexpected sms response [%v] [%v]", res, err)
+	}
+	_, err = client.Call(NewCall("5215511111111", "http://localhost/answer.xml"))
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("expected [%v] actual [%v]", ErrBadRequest, err)
+	}
+	if reqs := srv.Requests(); len(reqs) != 3 || reqs[0].Params.Get("api_secret") != "456" {
+		t.Errorf("unexpected requests [%v]", reqs)
+	}
+
+	receipts := make(chan *sms.DeliveryReceipt, 2)
+	dlr := httptest.NewServer(sms.NewDeliveryReceiptHandler(func(ctx context.Context, d *sms.DeliveryReceipt) error {
+		receipts <- d
+		return nil
+	}))
+	defer dlr.Close()
+	srv.ReceiptURL = dlr.URL
+	ids := srv.MessageIDs("5215522334455")
+	if len(ids) != 2 {
+		t.Fatalf("expected 2 messages actual [%v]", ids)
+	}
+	if err := srv.DeliveryReceipt(ids[0]); err != nil {
+		t.Fatal(err)
+	}
+	d := <-receipts
+	if d.MessageID != ids[0] || d.Status != "failed" || d.ErrCode != sms.StatusHandsetBusy {
+		t.Errorf("unexpected receipt [%+v]", d)
+	}
+	if err := srv.DeliveryReceipt("unknown"); err != nexmotest.ErrUnknownMessage {
+		t.Errorf("expected [%v] actual [%v]", nexmotest.ErrUnknownMessage, err)
+	}
+}
+
+func TestFakeClient(t *testing.T) {
+	var c Client = nexmotest.NewFake()
+	res, err := c.SMS(NewSMS("5215522334455", "NexmoTest", "Hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// responses are copies, changing one does not change the next.
+	res.Messages[0].Status = sms.StatusHandsetBusy
+	res, err = c.SMS(NewSMS("5215522334455", "NexmoTest", "Hello"))
+	if err != nil || res.Messages[0].Status != sms.StatusOK {
+		t.Errorf("expected [%v] actual [%v] [%v]", sms.StatusOK, res.Messages[0].Status, err)
+	}
+	fake := c.(*nexmotest.Fake)
+	fake.CallErr = ErrBadRequest
+	_, err = c.Call(NewCall("5215522334455", "http://localhost/answer.xml"))
+	if err != ErrBadRequest {
+		t.Errorf("expected [%v] actual [%v]", ErrBadRequest, err)
+	}
+	if len(fake.SMSRequests()) != 2 || len(fake.CallRequests()) != 1 || len(fake.Text2SpeechRequests()) != 0 {
+		t.Errorf("unexpected recorded requests")
+	}
+}
+
+func TestVoice(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type call struct {
+		Method string
+		Path   string
+		Body   string
+	}
+	var calls []call
+	h := func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		claims, err := voice.VerifyToken(token, &key.PublicKey, time.Now())
+		if err != nil || claims["application_id"] != "app-id" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"type":"UNAUTHORIZED","title":"Bad Token"}`))
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		calls = append(calls, call{r.Method, r.URL.RequestURI(), string(b)})
+		switch {
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"uuid":"call-1","status":"started","direction":"outbound"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/calls":
+			_, _ = w.Write([]byte(`{"count":1,"_embedded":{"calls":[{"uuid":"call-1","status":"answered"}]}}`))
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"uuid":"call-1","status":"completed","price":"0.01"}`))
+		case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/stream"):
+			_, _ = w.Write([]byte(`{"message":"Stream started","uuid":"call-1"}`))
+		case r.Method == http.MethodDelete:
+			_, _ = w.Write([]byte(`{"message":"Stream stopped","uuid":"call-1"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+	client := newTestClient(t, h, WithJWTGenerator(&voice.JWTGenerator{ApplicationID: "app-id", PrivateKey: key}))
+	ctx := context.Background()
+
+	created, err := client.CreateCall(ctx, &voice.CreateCallRequest{
+		To:        []voice.Endpoint{voice.Phone("5215522334455")},
+		From:      voice.Phone("12015550123"),
+		AnswerURL: []string{"http://localhost/answer"},
+	})
+	if err != nil || created.UUID != "call-1" {
+		t.Fatalf("create : unexpected [%v] [%v]", created, err)
+	}
+	list, err := client.ListCalls(ctx, &voice.ListCallsFilter{Status: "answered"})
+	if err != nil || len(list.Calls()) != 1 {
+		t.Errorf("list : unexpected [%v] [%v]", list, err)
+	}
+	c, err := client.GetCall(ctx, "call-1")
+	if err != nil || c.Price != "0.01" {
+		t.Errorf("get : unexpected [%v] [%v]", c, err)
+	}
+	if err := client.ModifyCall(ctx, "call-1", voice.Transfer("http://localhost/ncco")); err != nil {
+		t.Errorf("modify : err [%v]", err)
+	}
+	if _, err := client.StreamCall(ctx, "call-1", &voice.StreamRequest{StreamURL: []string{"http://localhost/a.mp3"}}); err != nil {
+		t.Errorf("stream : err [%v]", err)
+	}
+	if _, err := client.StopStreamCall(ctx, "call-1"); err != nil {
+		t.Errorf("stop stream : err [%v]", err)
+	}
+	expected := []call{
+		{"POST", "/v1/calls", `{"to":[{"type":"phone","number":"5215522334455"}],"from":{"type":"phone","number":"12015550123"},"answer_url":["http://localhost/answer"]}`},
+		{"GET", "/v1/calls?status=answered", ""},
+		{"GET", "/v1/calls/call-1", ""},
+		{"PUT", "/v1/calls/call-1", `{"action":"transfer","destination":{"type":"ncco","url":["http://localhost/ncco"]}}`},
+		{"PUT", "/v1/calls/call-1/stream", `{"stream_url":["http://localhost/a.mp3"]}`},
+		{"DELETE", "/v1/calls/call-1/stream", ""},
+	}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected calls [%v] actual [%v]", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("%d : expected [%v] actual [%v]", i, expected[i], calls[i])
+		}
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	bad := newTestClient(t, h, WithJWTGenerator(&voice.JWTGenerator{ApplicationID: "app-id", PrivateKey: other}))
+	_, err = bad.GetCall(ctx, "call-1")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.ErrorText != "Bad Token" {
+		t.Errorf("expected unauthorized actual [%v]", err)
+	}
+	_, err = Must("123", "456", time.Second).GetCall(ctx, "call-1")
+	if err != ErrNoApplication {
+		t.Errorf("expected [%v] actual [%v]", ErrNoApplication, err)
+	}
+}
@@ -0,0 +1,161 @@
+package nexmotest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jimmy-go/nexmo/call"
+	"github.com/jimmy-go/nexmo/sms"
+	"github.com/jimmy-go/nexmo/text2speech"
+)
+
+// Fake in memory implementation of nexmo.Client. It records every
+// request and returns a copy of the configured responses.
+type Fake struct {
+	// SMSFunc when set is called instead of returning
+	// SMSResponse and SMSErr.
+	SMSFunc     func(ctx context.Context, r *sms.Request) (*sms.Response, error)
+	SMSResponse *sms.Response
+	SMSErr      error
+
+	// CallFunc when set is called instead of returning
+	// CallResponse and CallErr.
+	CallFunc     func(ctx context.Context, r *call.Request) (*call.Response, error)
+	CallResponse *call.Response
+	CallErr      error
+
+	// Text2SpeechFunc when set is called instead of returning
+	// Text2SpeechResponse and Text2SpeechErr.
+	Text2SpeechFunc     func(ctx context.Context, r *text2speech.Request) (*text2speech.Response, error)
+	Text2SpeechResponse *text2speech.Response
+	Text2SpeechErr      error
+
+	sms  []*sms.Request
+	call []*call.Request
+	tts  []*text2speech.Request
+	sync.Mutex
+}
+
+// NewFake returns a Fake answering every request as accepted.
+func NewFake() *Fake {
+	return &Fake{
+		SMSResponse: &sms.Response{
+			MessageCount: "1",
+			Messages: []*sms.Message{
+				{Status: sms.StatusOK, MessageID: "0000000000000001"},
+			},
+		},
+		CallResponse: &call.Response{
+			CallID: "00000000000000000000000000000001",
+		},
+		Text2SpeechResponse: &text2speech.Response{
+			CallID: "00000000000000000000000000000001",
+			Status: text2speech.StatusOK,
+		},
+	}
+}
+
+// SMS implements nexmo.SMSSender.
+func (f *Fake) SMS(r *sms.Request) (*sms.Response, error) {
+	return f.SMSContext(context.Background(), r)
+}
+
+// SMSContext implements nexmo.SMSSender.
+func (f *Fake) SMSContext(ctx context.Context, r *sms.Request) (*sms.Response, error) {
+	f.Lock()
+	f.sms = append(f.sms, r)
+	fn, res, err := f.SMSFunc, copySMS(f.SMSResponse), f.SMSErr
+	f.Unlock()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if fn != nil {
+		return fn(ctx, r)
+	}
+	return res, err
+}
+
+// Call implements nexmo.Caller.
+func (f *Fake) Call(r *call.Request) (*call.Response, error) {
+	return f.CallContext(context.Background(), r)
+}
+
+// CallContext implements nexmo.Caller.
+func (f *Fake) CallContext(ctx context.Context, r *call.Request) (*call.Response, error) {
+	f.Lock()
+	f.call = append(f.call, r)
+	fn, res, err := f.CallFunc, f.CallResponse, f.CallErr
+	if res != nil {
+		c := *res
+		res = &c
+	}
+	f.Unlock()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if fn != nil {
+		return fn(ctx, r)
+	}
+	return res, err
+}
+
+// Text2Speech implements nexmo.Speaker.
+func (f *Fake) Text2Speech(r *text2speech.Request) (*text2speech.Response, error) {
+	return f.Text2SpeechContext(context.Background(), r)
+}
+
+// Text2SpeechContext implements nexmo.Speaker.
+func (f *Fake) Text2SpeechContext(ctx context.Context, r *text2speech.Request) (*text2speech.Response, error) {
+	f.Lock()
+	f.tts = append(f.tts, r)
+	fn, res, err := f.Text2SpeechFunc, f.Text2SpeechResponse, f.Text2SpeechErr
+	if res != nil {
+		c := *res
+		res = &c
+	}
+	f.Unlock()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if fn != nil {
+		return fn(ctx, r)
+	}
+	return res, err
+}
+
+// copySMS returns a deep copy of res.
+func copySMS(res *sms.Response) *sms.Response {
+	if res == nil {
+		return nil
+	}
+	c := *res
+	c.Messages = make([]*sms.Message, len(res.Messages))
+	for i, m := range res.Messages {
+		if m != nil {
+			cm := *m
+			c.Messages[i] = &cm
+		}
+	}
+	return &c
+}
+
+// SMSRequests returns SMS requests received.
+func (f *Fake) SMSRequests() []*sms.Request {
+	f.Lock()
+	defer f.Unlock()
+	return append([]*sms.Request(nil), f.sms...)
+}
+
+// CallRequests returns Call requests received.
+func (f *Fake) CallRequests() []*call.Request {
+	f.Lock()
+	defer f.Unlock()
+	return append([]*call.Request(nil), f.call...)
+}
+
+// Text2SpeechRequests returns Text2Speech requests received.
+func (f *Fake) Text2SpeechRequests() []*text2speech.Request {
+	f.Lock()
+	defer f.Unlock()
+	return append([]*text2speech.Request(nil), f.tts...)
+}
@@ -0,0 +1,319 @@
+// Package nexmotest contains an in-process fake Nexmo server for
+// offline tests.
+//
+//	srv := nexmotest.NewServer()
+//	defer srv.Close()
+//	client, err := nexmo.NewWithOptions(key, secret, nexmo.WithBaseURL(srv.URL, srv.URL))
+package nexmotest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jimmy-go/nexmo/sms"
+)
+
+// ErrUnknownMessage returned when a delivery receipt is requested
+// for a message the server did not send.
+var ErrUnknownMessage = errors.New("nexmotest: unknown message")
+
+// Request received by the fake server.
+type Request struct {
+	// Endpoint "sms", "call" or "text2speech".
+	Endpoint string
+	Method   string
+	Params   url.Values
+}
+
+// SMSResult scripted SMS response for a recipient.
+type SMSResult struct {
+	// HTTPStatus default 200.
+	HTTPStatus int
+
+	// Status message status, default sms.StatusOK.
+	Status sms.Status
+
+	ErrorText        string
+	MessagePrice     string
+	RemainingBalance string
+	Network          string
+
+	// ReceiptStatus delivery receipt status, default "delivered".
+	ReceiptStatus string
+
+	// ReceiptErrCode delivery receipt err-code, default "0".
+	ReceiptErrCode sms.Status
+}
+
+// CallResult scripted Call or Text2Speech response for a recipient.
+type CallResult struct {
+	// HTTPStatus default 200.
+	HTTPStatus int
+
+	Status    int
+	ErrorText string
+}
+
+// Server fake Nexmo server implementing SMS, Call and TTS endpoints.
+type Server struct {
+	*httptest.Server
+
+	// ReceiptURL delivery receipts destination, see DeliveryReceipt.
+	ReceiptURL string
+
+	// HTTPClient used to send delivery receipts.
+	HTTPClient *http.Client
+
+	requests []*Request
+	sms      map[string]*SMSResult
+	calls    map[string]*CallResult
+	tts      map[string]*CallResult
+	messages map[string]*sentMessage
+	seq      int
+	sync.Mutex
+}
+
+// sentMessage message sent through the fake server.
+type sentMessage struct {
+	id     string
+	to     string
+	from   string
+	ref    string
+	result *SMSResult
+}
+
+// NewServer starts a new fake server.
+func NewServer() *Server {
+	s := &Server{
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		sms:        make(map[string]*SMSResult),
+		calls:      make(map[string]*CallResult),
+		tts:        make(map[string]*CallResult),
+		messages:   make(map[string]*sentMessage),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sms/json", s.handleSMS)
+	mux.HandleFunc("/call/json", s.handleCall("call", s.calls, "call-id", "error-text"))
+	mux.HandleFunc("/tts/json", s.handleCall("text2speech", s.tts, "call_id", "error_text"))
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// SetSMS scripts SMS responses for to.
+func (s *Server) SetSMS(to string, r SMSResult) {
+	s.Lock()
+	s.sms[to] = &r
+	s.Unlock()
+}
+
+// SetCall scripts Call responses for to.
+func (s *Server) SetCall(to string, r CallResult) {
+	s.Lock()
+	s.calls[to] = &r
+	s.Unlock()
+}
+
+// SetText2Speech scripts Text2Speech responses for to.
+func (s *Server) SetText2Speech(to string, r CallResult) {
+	s.Lock()
+	s.tts[to] = &r
+	s.Unlock()
+}
+
+// Requests returns every request received.
+func (s *Server) Requests() []*Request {
+	s.Lock()
+	defer s.Unlock()
+	return append([]*Request(nil), s.requests...)
+}
+
+// Reset removes recorded requests, messages and scripted responses.
+func (s *Server) Reset() {
+	s.Lock()
+	s.requests = nil
+	s.sms = make(map[string]*SMSResult)
+	s.calls = make(map[string]*CallResult)
+	s.tts = make(map[string]*CallResult)
+	s.messages = make(map[string]*sentMessage)
+	s.Unlock()
+}
+
+// record parses and saves r. It returns false after answering 401
+// when credentials are missing.
+func (s *Server) record(endpoint string, w http.ResponseWriter, r *http.Request) (url.Values, bool) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	s.Lock()
+	s.requests = append(s.requests, &Request{
+		Endpoint: endpoint,
+		Method:   r.Method,
+		Params:   r.Form,
+	})
+	s.Unlock()
+	if len(r.Form.Get("api_key")) < 1 || (len(r.Form.Get("api_secret")) < 1 && len(r.Form.Get("sig")) < 1) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error-text": "Bad Credentials"})
+		return nil, false
+	}
+	return r.Form, true
+}
+
+func (s *Server) handleSMS(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.record("sms", w, r)
+	if !ok {
+		return
+	}
+	to := p.Get("to")
+	s.Lock()
+	defer s.Unlock()
+	result := &SMSResult{}
+	if scripted, ok := s.sms[to]; ok {
+		result = scripted
+	}
+	if result.HTTPStatus != 0 && result.HTTPStatus != http.StatusOK {
+		writeJSON(w, result.HTTPStatus, map[string]string{"error-text": result.ErrorText})
+		return
+	}
+	parts := 1
+	if t := p.Get("type"); t == "" || t == sms.TypeText || t == sms.TypeUnicode {
+		parts = sms.Analyze(p.Get("text")).Segments
+	}
+	status := result.Status
+	if len(status) < 1 {
+		status = sms.StatusOK
+	}
+	res := &sms.Response{
+		MessageCount: fmt.Sprint(parts),
+	}
+	for i := 0; i < parts; i++ {
+		s.seq++
+		m := &sms.Message{
+			Status:           status,
+			To:               to,
+			ClientRef:        p.Get("client-ref"),
+			RemainingBalance: result.RemainingBalance,
+			MessagePrice:     result.MessagePrice,
+			Network:          result.Network,
+			ErrorText:        result.ErrorText,
+		}
+		if status == sms.StatusOK {
+			m.MessageID = fmt.Sprintf("%016X", s.seq)
+			s.messages[m.MessageID] = &sentMessage{
+				id:     m.MessageID,
+				to:     to,
+				from:   p.Get("from"),
+				ref:    p.Get("client-ref"),
+				result: result,
+			}
+		}
+		res.Messages = append(res.Messages, m)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// handleCall handles call and text2speech endpoints, they only
+// differ in JSON field names.
+func (s *Server) handleCall(endpoint string, scripts map[string]*CallResult, idKey, errKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, ok := s.record(endpoint, w, r)
+		if !ok {
+			return
+		}
+		to := p.Get("to")
+		s.Lock()
+		defer s.Unlock()
+		result := &CallResult{}
+		if scripted, ok := scripts[to]; ok {
+			result = scripted
+		}
+		status := http.StatusOK
+		if result.HTTPStatus != 0 {
+			status = result.HTTPStatus
+		}
+		s.seq++
+		res := map[string]interface{}{
+			idKey:    fmt.Sprintf("%032x", s.seq),
+			"to":     to,
+			"status": result.Status,
+			errKey:   result.ErrorText,
+		}
+		// text2speech status is a string.
+		if endpoint == "text2speech" {
+			res["status"] = fmt.Sprint(result.Status)
+		}
+		writeJSON(w, status, res)
+	}
+}
+
+// DeliveryReceipt sends the delivery receipt of messageID to
+// ReceiptURL as a form POST using the scripted receipt status.
+func (s *Server) DeliveryReceipt(messageID string) error {
+	s.Lock()
+	m, ok := s.messages[messageID]
+	target := s.ReceiptURL
+	s.Unlock()
+	if !ok {
+		return ErrUnknownMessage
+	}
+	status := m.result.ReceiptStatus
+	if len(status) < 1 {
+		status = "delivered"
+	}
+	errCode := m.result.ReceiptErrCode
+	if len(errCode) < 1 {
+		errCode = sms.StatusOK
+	}
+	now := time.Now().UTC()
+	form := url.Values{
+		"msisdn":            {m.to},
+		"to":                {m.from},
+		"network-code":      {m.result.Network},
+		"messageId":         {m.id},
+		"price":             {m.result.MessagePrice},
+		"status":            {status},
+		"scts":              {now.Format("0601021504")},
+		"err-code":          {string(errCode)},
+		"message-timestamp": {now.Format("2006-01-02 15:04:05")},
+		"client-ref":        {m.ref},
+	}
+	resp, err := s.HTTPClient.Post(target, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("nexmotest: delivery receipt : status [%d]", resp.StatusCode)
+	}
+	return nil
+}
+
+// MessageIDs returns ids of accepted messages sent to to.
+func (s *Server) MessageIDs(to string) []string {
+	s.Lock()
+	defer s.Unlock()
+	var ids []string
+	for id, m := range s.messages {
+		if m.to == to {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
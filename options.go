@@ -0,0 +1,124 @@
+package nexmo
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jimmy-go/nexmo/signature"
+	"github.com/jimmy-go/nexmo/voice"
+)
+
+// Option configures a Nexmo client, see NewWithOptions.
+type Option func(*Nexmo)
+
+// WithHTTPClient sets the http.Client used for every request,
+// e.g. to use a proxy transport or mTLS.
+func WithHTTPClient(c *http.Client) Option {
+	return func(x *Nexmo) {
+		if c != nil {
+			x.client = c
+		}
+	}
+}
+
+// WithBaseURL replaces BaseURLRest with rest and BaseURLAPI with
+// api in every endpoint. Useful to point the client to a local
+// server in tests.
+func WithBaseURL(rest, api string) Option {
+	return func(x *Nexmo) {
+		if len(api) > 0 {
+			x.apiURL = api
+		}
+		for _, sup := range x.endpoints {
+			switch {
+			case len(rest) > 0 && strings.HasPrefix(sup.URL, BaseURLRest):
+				sup.URL = strings.TrimSuffix(rest, "/") + strings.TrimPrefix(sup.URL, BaseURLRest)
+			case len(api) > 0 && strings.HasPrefix(sup.URL, BaseURLAPI):
+				sup.URL = strings.TrimSuffix(api, "/") + strings.TrimPrefix(sup.URL, BaseURLAPI)
+			}
+		}
+	}
+}
+
+// WithUserAgent sets User-Agent header for every request.
+func WithUserAgent(ua string) Option {
+	return func(x *Nexmo) {
+		x.userAgent = ua
+	}
+}
+
+// WithTimeout sets request timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(x *Nexmo) {
+		x.timeout = d
+	}
+}
+
+// WithRetryPolicy same as SetRetryPolicy.
+func WithRetryPolicy(p *RetryPolicy) Option {
+	return func(x *Nexmo) {
+		x.retryPolicy = p
+	}
+}
+
+// WithSMSStatusErrors same as SetSMSStatusErrors.
+func WithSMSStatusErrors(enabled bool) Option {
+	return func(x *Nexmo) {
+		x.statusErrors = enabled
+	}
+}
+
+// WithLogger logs every request and response with l. Secrets
+// are redacted. By default the client does not log.
+func WithLogger(l *log.Logger) Option {
+	return func(x *Nexmo) {
+		x.logger = l
+	}
+}
+
+// WithSignatureSecret signs SMS requests with secret using method
+// instead of sending api_secret. Nexmo requires md5hash, md5,
+// sha1, sha256 or sha512 as configured in your account.
+//
+// see: https://docs.nexmo.com/messaging/signing-messages
+func WithSignatureSecret(secret string, method signature.Method) Option {
+	return func(x *Nexmo) {
+		x.sigSecret = secret
+		x.sigMethod = method
+	}
+}
+
+// WithNumberNormalizer normalizes To of every request, and numeric
+// senders, to digits only international format before sending.
+// National numbers are parsed with defaultRegion, e.g. "MX".
+func WithNumberNormalizer(defaultRegion string) Option {
+	return func(x *Nexmo) {
+		x.numberRegion = defaultRegion
+		x.normalize = true
+	}
+}
+
+// WithRateLimit limits requests to endpoint ("sms", "call" or
+// "text2speech"). The rate adapts when Nexmo answers with throttle
+// responses, see Nexmo.Rate.
+func WithRateLimit(endpoint string, l RateLimit) Option {
+	return func(x *Nexmo) {
+		if l.PerSecond <= 0 {
+			return
+		}
+		if x.limiters == nil {
+			x.limiters = make(map[string]*rateLimiter)
+		}
+		x.limiters[endpoint] = newRateLimiter(l)
+	}
+}
+
+// WithJWTGenerator authenticates Voice API requests as the
+// application of g.
+func WithJWTGenerator(g *voice.JWTGenerator) Option {
+	return func(x *Nexmo) {
+		x.jwt = g
+	}
+}
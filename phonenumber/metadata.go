@@ -0,0 +1,87 @@
+package phonenumber
+
+import "strconv"
+
+// region numbering metadata.
+type region struct {
+	// Code country calling code.
+	Code int
+
+	// Trunk national prefix dialed before national numbers.
+	Trunk string
+
+	// Min and Max national significant number length.
+	Min int
+	Max int
+}
+
+// regions lightweight numbering metadata by ISO 3166 region code.
+var regions = map[string]region{
+	"AR": {54, "0", 10, 11},
+	"AT": {43, "0", 4, 13},
+	"AU": {61, "0", 9, 9},
+	"BE": {32, "0", 8, 9},
+	"BR": {55, "0", 10, 11},
+	"CA": {1, "1", 10, 10},
+	"CH": {41, "0", 9, 9},
+	"CL": {56, "", 9, 9},
+	"CN": {86, "0", 9, 11},
+	"CO": {57, "", 8, 10},
+	"DE": {49, "0", 6, 13},
+	"DK": {45, "", 8, 8},
+	"ES": {34, "", 9, 9},
+	"FI": {358, "0", 5, 12},
+	"FR": {33, "0", 9, 9},
+	"GB": {44, "0", 9, 10},
+	"GR": {30, "", 10, 10},
+	"HK": {852, "", 8, 8},
+	"IE": {353, "0", 7, 9},
+	"IL": {972, "0", 8, 9},
+	"IN": {91, "0", 10, 10},
+	"IT": {39, "", 6, 11},
+	"JP": {81, "0", 9, 10},
+	"KR": {82, "0", 8, 10},
+	"MX": {52, "", 10, 11},
+	"NL": {31, "0", 9, 9},
+	"NO": {47, "", 8, 8},
+	"NZ": {64, "0", 8, 10},
+	"PE": {51, "0", 8, 9},
+	"PH": {63, "0", 8, 10},
+	"PL": {48, "", 9, 9},
+	"PT": {351, "", 9, 9},
+	"RU": {7, "8", 10, 10},
+	"SE": {46, "0", 7, 9},
+	"SG": {65, "", 8, 8},
+	"TR": {90, "0", 10, 10},
+	"US": {1, "1", 10, 10},
+	"VE": {58, "0", 10, 10},
+	"ZA": {27, "0", 9, 9},
+}
+
+// callingCodes assigned ITU-T E.164 country calling codes by
+// their decimal digits.
+var callingCodes = map[string]bool{}
+
+func init() {
+	codes := []int{
+		1, 7, 20, 27, 30, 31, 32, 33, 34, 36, 39, 40, 41, 43, 44, 45, 46,
+		47, 48, 49, 51, 52, 53, 54, 55, 56, 57, 58, 60, 61, 62, 63, 64, 65,
+		66, 81, 82, 84, 86, 90, 91, 92, 93, 94, 95, 98, 211, 212, 213, 216,
+		218, 220, 221, 222, 223, 224, 225, 226, 227, 228, 229, 230, 231,
+		232, 233, 234, 235, 236, 237, 238, 239, 240, 241, 242, 243, 244,
+		245, 246, 247, 248, 249, 250, 251, 252, 253, 254, 255, 256, 257,
+		258, 260, 261, 262, 263, 264, 265, 266, 267, 268, 269, 290, 291,
+		297, 298, 299, 350, 351, 352, 353, 354, 355, 356, 357, 358, 359,
+		370, 371, 372, 373, 374, 375, 376, 377, 378, 379, 380, 381, 382,
+		383, 385, 386, 387, 389, 420, 421, 423, 500, 501, 502, 503, 504,
+		505, 506, 507, 508, 509, 590, 591, 592, 593, 594, 595, 596, 597,
+		598, 599, 670, 672, 673, 674, 675, 676, 677, 678, 679, 680, 681,
+		682, 683, 685, 686, 687, 688, 689, 690, 691, 692, 850, 852, 853,
+		855, 856, 880, 886, 960, 961, 962, 963, 964, 965, 966, 967, 968,
+		970, 971, 972, 973, 974, 975, 976, 977, 992, 993, 994, 995, 996,
+		998,
+	}
+	for _, c := range codes {
+		callingCodes[strconv.Itoa(c)] = true
+	}
+}
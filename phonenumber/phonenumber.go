@@ -0,0 +1,146 @@
+// Package phonenumber normalizes phone numbers to the digits only
+// E.164 international format used by Nexmo.
+package phonenumber
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidCharacter returned when number has characters
+	// other than digits and formatting.
+	ErrInvalidCharacter = errors.New("phonenumber: invalid character")
+
+	// ErrUnknownRegion returned when a national number is parsed
+	// without a known default region.
+	ErrUnknownRegion = errors.New("phonenumber: unknown region")
+
+	// ErrInvalidCountryCode returned when the country calling
+	// code is not assigned.
+	ErrInvalidCountryCode = errors.New("phonenumber: invalid country calling code")
+
+	// ErrInvalidLength returned when number is too short or long.
+	ErrInvalidLength = errors.New("phonenumber: invalid length")
+)
+
+// Number parsed phone number.
+type Number struct {
+	// CountryCode country calling code, e.g. 52.
+	CountryCode int
+
+	// National national significant number.
+	National string
+}
+
+// Digits returns number in digits only international format,
+// e.g. 5215522334455, as expected by Nexmo.
+func (n *Number) Digits() string {
+	return strconv.Itoa(n.CountryCode) + n.National
+}
+
+// E164 returns number in E.164 format, e.g. +5215522334455.
+func (n *Number) E164() string {
+	return "+" + n.Digits()
+}
+
+// Parse parses s in international form (+52..., 0052...) or in
+// national form of defaultRegion, an ISO 3166 code like "MX".
+// Digits that are not a valid national number are tried as
+// international without prefix, e.g. 5215522334455.
+// Spaces, dashes, dots, slashes and parenthesis are ignored.
+func Parse(s, defaultRegion string) (*Number, error) {
+	digits, plus, err := clean(s)
+	if err != nil {
+		return nil, err
+	}
+	reg, known := regions[strings.ToUpper(defaultRegion)]
+	switch {
+	case plus:
+		return international(digits)
+	case strings.HasPrefix(digits, "00"):
+		return international(digits[2:])
+	case known && reg.Code == 1 && strings.HasPrefix(digits, "011"):
+		return international(digits[3:])
+	case !known:
+		return fallback(digits, ErrUnknownRegion)
+	}
+	national := digits
+	if len(reg.Trunk) > 0 && strings.HasPrefix(national, reg.Trunk) && len(national)-len(reg.Trunk) >= reg.Min {
+		national = national[len(reg.Trunk):]
+	}
+	if len(national) < reg.Min || len(national) > reg.Max {
+		return fallback(digits, ErrInvalidLength)
+	}
+	return &Number{CountryCode: reg.Code, National: national}, nil
+}
+
+// fallback parses digits already in international format without
+// prefix, e.g. 5215522334455, returning err when they are not.
+func fallback(digits string, err error) (*Number, error) {
+	n, ierr := international(digits)
+	if ierr != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Normalize parses s with defaultRegion and returns digits only
+// international format.
+func Normalize(s, defaultRegion string) (string, error) {
+	n, err := Parse(s, defaultRegion)
+	if err != nil {
+		return "", err
+	}
+	return n.Digits(), nil
+}
+
+// clean removes formatting from s and reports leading +.
+func clean(s string) (string, bool, error) {
+	s = strings.TrimSpace(s)
+	plus := strings.HasPrefix(s, "+")
+	if plus {
+		s = s[1:]
+	}
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case strings.ContainsRune(" -.()/\t", r):
+		default:
+			return "", false, ErrInvalidCharacter
+		}
+	}
+	return b.String(), plus, nil
+}
+
+// international splits digits in country calling code and
+// national number. Calling codes never start with 0.
+func international(digits string) (*Number, error) {
+	if strings.HasPrefix(digits, "0") {
+		return nil, ErrInvalidCountryCode
+	}
+	for i := 1; i <= 3 && i < len(digits); i++ {
+		if !callingCodes[digits[:i]] {
+			continue
+		}
+		code, _ := strconv.Atoi(digits[:i])
+		n := &Number{CountryCode: code, National: digits[i:]}
+		if len(n.National) < 4 || len(digits) > 15 {
+			return nil, ErrInvalidLength
+		}
+		for _, reg := range regions {
+			if reg.Code != code {
+				continue
+			}
+			if len(n.National) < reg.Min || len(n.National) > reg.Max {
+				return nil, ErrInvalidLength
+			}
+			break
+		}
+		return n, nil
+	}
+	return nil, ErrInvalidCountryCode
+}
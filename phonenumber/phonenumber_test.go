@@ -0,0 +1,45 @@
+// Package phonenumber contains tests for phonenumber package.
+package phonenumber
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	table := []struct {
+		Input    string
+		Region   string
+		Expected string
+		Err      error
+	}{
+		{"+52 1 55 2233 4455", "", "5215522334455", nil},
+		{"(555) 123-4567", "US", "15551234567", nil},
+		{"1 (555) 123-4567", "US", "15551234567", nil},
+		{"011 44 20 7946 0018", "US", "442079460018", nil},
+		{"020 7946 0018", "GB", "442079460018", nil},
+		{"0044 20 7946 0018", "MX", "442079460018", nil},
+		{"06 12 34 56 78", "fr", "33612345678", nil},
+		{"030 123456", "DE", "4930123456", nil},
+		{"55 2233 4455", "MX", "525522334455", nil},
+		{"5215522334455", "MX", "5215522334455", nil},
+		{"447700900123", "GB", "447700900123", nil},
+		{"5215522334455", "", "5215522334455", nil},
+		{"555-1234", "", "", ErrUnknownRegion},
+		{"12345", "MX", "", ErrInvalidLength},
+		{"+999 1234 5678", "", "", ErrInvalidCountryCode},
+		{"020 7946 0018", "", "", ErrUnknownRegion},
+		{"0521234567890", "US", "", ErrInvalidLength},
+		{"+0521234567890", "", "", ErrInvalidCountryCode},
+		{"+1 555 123", "", "", ErrInvalidLength},
+		{"555 CALL NOW", "US", "", ErrInvalidCharacter},
+	}
+	for i := range table {
+		x := table[i]
+		actual, err := Normalize(x.Input, x.Region)
+		if err != x.Err || actual != x.Expected {
+			t.Errorf("%d : expected [%s] [%v] actual [%s] [%v]", i, x.Expected, x.Err, actual, err)
+		}
+	}
+	n, _ := Parse("+52 1 55 2233 4455", "")
+	if n.E164() != "+5215522334455" || n.CountryCode != 52 {
+		t.Errorf("unexpected number [%+v]", n)
+	}
+}
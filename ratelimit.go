@@ -0,0 +1,95 @@
+package nexmo
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRateLimited returned when ctx deadline passes before the
+// rate limiter allows the request.
+var ErrRateLimited = errors.New("nexmo: rate limited")
+
+// RateLimit configures the client side rate limiter of an endpoint.
+type RateLimit struct {
+	// PerSecond requests per second allowed.
+	PerSecond float64
+
+	// Burst requests allowed at once. Default 1.
+	Burst int
+
+	// MinPerSecond lower limit when the rate is reduced after
+	// throttle responses. Default PerSecond / 10.
+	MinPerSecond float64
+}
+
+// rateLimiter adaptive token bucket. The rate is halved on every
+// throttle response and recovers slowly on success.
+type rateLimiter struct {
+	bucket *tokenBucket
+	max    float64
+	min    float64
+}
+
+func newRateLimiter(l RateLimit) *rateLimiter {
+	min := l.MinPerSecond
+	if min <= 0 || min > l.PerSecond {
+		min = l.PerSecond / 10
+	}
+	return &rateLimiter{
+		bucket: newTokenBucket(l.PerSecond, l.Burst),
+		max:    l.PerSecond,
+		min:    min,
+	}
+}
+
+// Wait blocks until the request is allowed. When ctx deadline
+// would pass first it fails fast with ErrRateLimited.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	err := l.bucket.Wait(ctx)
+	if err == context.DeadlineExceeded && ctx.Err() == nil {
+		return ErrRateLimited
+	}
+	return err
+}
+
+// throttled halves the rate.
+func (l *rateLimiter) throttled() {
+	l.bucket.scaleRate(0.5, l.min, l.max)
+}
+
+// succeeded increases the rate 5% up to the configured rate.
+func (l *rateLimiter) succeeded() {
+	l.bucket.scaleRate(1.05, l.min, l.max)
+}
+
+// Rate returns current requests per second allowed for endpoint
+// ("sms", "call" or "text2speech") or 0 when it has no limit.
+func (x *Nexmo) Rate(endpoint string) float64 {
+	l, ok := x.limiters[endpoint]
+	if !ok {
+		return 0
+	}
+	return l.bucket.Rate()
+}
+
+// wait applies endpoint rate limit if any.
+func (x *Nexmo) wait(ctx context.Context, endpoint string) error {
+	l, ok := x.limiters[endpoint]
+	if !ok {
+		return nil
+	}
+	return l.Wait(ctx)
+}
+
+// feedback adapts endpoint rate limit to a throttle response.
+func (x *Nexmo) feedback(endpoint string, throttled bool) {
+	l, ok := x.limiters[endpoint]
+	if !ok {
+		return
+	}
+	if throttled {
+		l.throttled()
+		return
+	}
+	l.succeeded()
+}
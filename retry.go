@@ -0,0 +1,157 @@
+package nexmo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jimmy-go/nexmo/sms"
+)
+
+// RetryPolicy configures automatic retries for temporary failures.
+type RetryPolicy struct {
+	// MaxAttempts total attempts including the first one.
+	MaxAttempts int
+
+	// BaseDelay first backoff delay, doubled on every attempt.
+	BaseDelay time.Duration
+
+	// MaxDelay backoff upper limit. Zero means no limit.
+	MaxDelay time.Duration
+
+	// Retryable reports if a failed attempt to endpoint ("sms",
+	// "call", "text2speech") should be retried. Nil uses
+	// DefaultRetryable.
+	Retryable func(endpoint string, err error) bool
+}
+
+// DefaultRetryable retries transport errors, HTTP 429 and 5xx
+// responses and SMS responses where every message part failed
+// with a temporary status or was throttled.
+func DefaultRetryable(endpoint string, err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var throttled *throttledError
+	if errors.As(err, &throttled) {
+		return true
+	}
+	var statusErrs sms.StatusErrors
+	if errors.As(err, &statusErrs) {
+		return statusErrs.Temporary()
+	}
+	switch err {
+	case ErrSupportNotFound, ErrEmptyResponse:
+		return false
+	}
+	return true
+}
+
+// throttledError SMS send response where every message part was
+// rejected by the account throughput limit.
+type throttledError struct {
+	errs sms.StatusErrors
+}
+
+func (e *throttledError) Error() string {
+	return e.errs.Error()
+}
+
+// Unwrap returns the message status errors.
+func (e *throttledError) Unwrap() error {
+	return e.errs
+}
+
+// SetRetryPolicy sets retry policy for every request. A nil
+// policy disables retries.
+func (x *Nexmo) SetRetryPolicy(p *RetryPolicy) {
+	x.Lock()
+	x.retryPolicy = p
+	x.Unlock()
+}
+
+// retry calls fn until it succeeds, the policy gives up or ctx is
+// done. The last fn error is returned.
+func (x *Nexmo) retry(ctx context.Context, endpoint string, fn func() error) error {
+	x.RLock()
+	p := x.retryPolicy
+	x.RUnlock()
+	err := fn()
+	if p == nil {
+		return err
+	}
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = DefaultRetryable
+	}
+	for attempt := 1; attempt < p.MaxAttempts; attempt++ {
+		if !retryable(endpoint, err) {
+			return err
+		}
+		wait := p.backoff(attempt)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > wait {
+			wait = apiErr.RetryAfter
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return err
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+		err = fn()
+	}
+	return err
+}
+
+// backoff returns exponential delay with jitter for attempt,
+// a random value between half and the full delay.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if p.MaxDelay > 0 && d > p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// parseRetryAfter parses Retry-After header in seconds or
+// HTTP date form.
+func parseRetryAfter(v string) time.Duration {
+	if len(v) < 1 {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
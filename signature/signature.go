@@ -0,0 +1,228 @@
+// Package signature contains Nexmo request signing and webhook
+// signature verification.
+//
+// see: https://docs.nexmo.com/messaging/signing-messages
+package signature
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnknownMethod returned for unsupported signature methods.
+	ErrUnknownMethod = errors.New("nexmo: signature : unknown method")
+
+	// ErrMissingSignature returned when sig or timestamp is empty.
+	ErrMissingSignature = errors.New("nexmo: signature : missing sig or timestamp")
+
+	// ErrInvalidSignature returned when sig does not match.
+	ErrInvalidSignature = errors.New("nexmo: signature : invalid signature")
+
+	// ErrInvalidTimestamp returned when timestamp is not a unix time.
+	ErrInvalidTimestamp = errors.New("nexmo: signature : invalid timestamp")
+
+	// ErrTimestampSkew returned when timestamp is too far from now.
+	ErrTimestampSkew = errors.New("nexmo: signature : timestamp out of range")
+)
+
+// Method signature hash method.
+type Method string
+
+const (
+	// MD5Hash md5 of params followed by the secret.
+	MD5Hash Method = "md5hash"
+
+	// MD5 HMAC-MD5.
+	MD5 Method = "md5"
+
+	// SHA1 HMAC-SHA1.
+	SHA1 Method = "sha1"
+
+	// SHA256 HMAC-SHA256.
+	SHA256 Method = "sha256"
+
+	// SHA512 HMAC-SHA512.
+	SHA512 Method = "sha512"
+)
+
+// DefaultMaxSkew accepted difference between timestamp and now.
+const DefaultMaxSkew = 5 * time.Minute
+
+// Sign returns the hex signature of params using secret. The sig
+// param is ignored if present.
+func Sign(params url.Values, secret string, method Method) (string, error) {
+	s := canonical(params)
+	var h hash.Hash
+	switch method {
+	case MD5Hash:
+		h = md5.New()
+		_, _ = io.WriteString(h, s+secret)
+		return hex.EncodeToString(h.Sum(nil)), nil
+	case MD5:
+		h = hmac.New(md5.New, []byte(secret))
+	case SHA1:
+		h = hmac.New(sha1.New, []byte(secret))
+	case SHA256:
+		h = hmac.New(sha256.New, []byte(secret))
+	case SHA512:
+		h = hmac.New(sha512.New, []byte(secret))
+	default:
+		return "", ErrUnknownMethod
+	}
+	_, _ = io.WriteString(h, s)
+	return strings.ToUpper(hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// canonical builds the string to sign: params sorted by key as
+// &key=value, with & and = inside values replaced by _.
+func canonical(params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k == "sig" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	r := strings.NewReplacer("&", "_", "=", "_")
+	var buf bytes.Buffer
+	for _, k := range keys {
+		buf.WriteString("&")
+		buf.WriteString(k)
+		buf.WriteString("=")
+		buf.WriteString(r.Replace(params.Get(k)))
+	}
+	return buf.String()
+}
+
+// Verify checks sig and timestamp params of params. Signatures are
+// compared case insensitive in constant time.
+func Verify(params url.Values, secret string, method Method, maxSkew time.Duration, now time.Time) error {
+	sig := params.Get("sig")
+	ts := params.Get("timestamp")
+	if len(sig) < 1 || len(ts) < 1 {
+		return ErrMissingSignature
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	skew := now.Sub(time.Unix(unix, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if maxSkew > 0 && skew > maxSkew {
+		return ErrTimestampSkew
+	}
+	expected, err := Sign(params, secret, method)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(strings.ToLower(sig)), []byte(strings.ToLower(expected))) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Verifier http middleware for signed Nexmo webhooks, e.g. inbound
+// SMS, delivery receipts and call/TTS callbacks.
+type Verifier struct {
+	// Secret signature secret.
+	Secret string
+
+	// Method signature method.
+	Method Method
+
+	// MaxSkew accepted timestamp difference. Zero uses
+	// DefaultMaxSkew.
+	MaxSkew time.Duration
+
+	// Now returns current time. Nil uses time.Now.
+	Now func() time.Time
+}
+
+// Middleware returns next wrapped with signature verification.
+// Invalid requests are answered 401 without calling next.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params, err := Params(r)
+		if err == nil {
+			err = v.Verify(params)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Verify checks params signature.
+func (v *Verifier) Verify(params url.Values) error {
+	maxSkew := v.MaxSkew
+	if maxSkew == 0 {
+		maxSkew = DefaultMaxSkew
+	}
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	return Verify(params, v.Secret, v.Method, maxSkew, now)
+}
+
+// Params reads webhook params from GET query, form POST or JSON
+// body. The body is restored so next handlers can read it.
+func Params(r *http.Request) (url.Values, error) {
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if r.Method != http.MethodPost || ct != "application/json" {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		return r.Form, nil
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	// numbers are kept verbatim, as Nexmo signed them.
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	for k, v := range m {
+		switch x := v.(type) {
+		case string:
+			params.Set(k, x)
+		case json.Number:
+			params.Set(k, x.String())
+		case nil:
+			params.Set(k, "")
+		default:
+			params.Set(k, fmt.Sprint(x))
+		}
+	}
+	return params, nil
+}
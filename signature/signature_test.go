@@ -0,0 +1,121 @@
+// Package signature contains tests for signature package.
+package signature
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// formRequest returns a form POST request with v as body.
+func formRequest(v url.Values) *http.Request {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(v.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+// jsonRequest returns a JSON POST request with s as body.
+func jsonRequest(s string) *http.Request {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(s))
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+func TestSign(t *testing.T) {
+	params := url.Values{
+		"b":         {"x&y=z"},
+		"a":         {"1"},
+		"timestamp": {"1461605396"},
+		"sig":       {"ignored"},
+	}
+	if s := canonical(params); s != "&a=1&b=x_y_z&timestamp=1461605396" {
+		t.Errorf("unexpected canonical [%s]", s)
+	}
+	sig, err := Sign(params, "secret", MD5Hash)
+	if err != nil || sig != "81ca4f15159f88b74387f77413df3c44" {
+		t.Errorf("unexpected md5hash [%s] [%v]", sig, err)
+	}
+	sig, err = Sign(params, "secret", SHA256)
+	if err != nil || sig != "C91B6EFCA255A903A88FAE6502850732CA013B8BA6846A5F27A6460F5E6E998F" {
+		t.Errorf("unexpected sha256 [%s] [%v]", sig, err)
+	}
+	lengths := map[Method]int{MD5: 32, SHA1: 40, SHA256: 64, SHA512: 128}
+	for m, l := range lengths {
+		sig, err := Sign(params, "secret", m)
+		if err != nil || len(sig) != l || strings.ToUpper(sig) != sig {
+			t.Errorf("%s : unexpected signature [%s] [%v]", m, sig, err)
+		}
+	}
+	if _, err := Sign(params, "secret", "crc32"); err != ErrUnknownMethod {
+		t.Errorf("expected [%v] actual [%v]", ErrUnknownMethod, err)
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	now := time.Unix(1461605396, 0)
+	v := &Verifier{
+		Secret: "secret",
+		Method: SHA256,
+		Now:    func() time.Time { return now },
+	}
+	signed := func(ts time.Time, secret string) url.Values {
+		p := url.Values{
+			"msisdn":    {"5215522334455"},
+			"messageId": {"02000000E68951D8"},
+			"text":      {"Hello & bye"},
+			"timestamp": {strconv.FormatInt(ts.Unix(), 10)},
+		}
+		sig, _ := Sign(p, secret, SHA256)
+		p.Set("sig", strings.ToLower(sig))
+		return p
+	}
+	jsonBody := `{"msisdn":"5215522334455","messageId":"02000000E68951D8","text":"Hello & bye","timestamp":"1461605396","sig":"` +
+		signed(now, "secret").Get("sig") + `"}`
+	table := []struct {
+		Request  *http.Request
+		Expected int
+	}{
+		{httptest.NewRequest("GET", "/?"+signed(now, "secret").Encode(), nil), http.StatusNoContent},
+		{formRequest(signed(now, "secret")), http.StatusNoContent},
+		{jsonRequest(jsonBody), http.StatusNoContent},
+		{httptest.NewRequest("GET", "/?"+signed(now, "other").Encode(), nil), http.StatusUnauthorized},
+		{httptest.NewRequest("GET", "/?"+signed(now.Add(-time.Hour), "secret").Encode(), nil), http.StatusUnauthorized},
+		{httptest.NewRequest("GET", "/?msisdn=1", nil), http.StatusUnauthorized},
+		// numeric timestamp is signed verbatim.
+		{jsonRequest(strings.Replace(jsonBody, `"1461605396"`, `1461605396`, 1)), http.StatusNoContent},
+	}
+	for i := range table {
+		x := table[i]
+		var body string
+		h := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseForm(); err != nil {
+				t.Error(err)
+			}
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, x.Request)
+		if w.Code != x.Expected {
+			t.Errorf("%d : expected status [%d] actual [%d]", i, x.Expected, w.Code)
+		}
+		if i == 2 && body != jsonBody {
+			t.Errorf("json body not restored [%s]", body)
+		}
+	}
+	p := signed(now, "secret")
+	p.Set("timestamp", "yesterday")
+	if err := Verify(p, "secret", SHA256, DefaultMaxSkew, now); err != ErrInvalidTimestamp {
+		t.Errorf("expected [%v] actual [%v]", ErrInvalidTimestamp, err)
+	}
+	p = signed(now.Add(-time.Hour), "secret")
+	if err := Verify(p, "secret", SHA256, DefaultMaxSkew, now); err != ErrTimestampSkew {
+		t.Errorf("expected [%v] actual [%v]", ErrTimestampSkew, err)
+	}
+}
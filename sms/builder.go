@@ -0,0 +1,204 @@
+package sms
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/jimmy-go/nexmo/internal/validate"
+)
+
+// FieldError names the request field that is not valid.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+// Error implements error interface.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("nexmo: sms : field [%s] %s", e.Field, e.Reason)
+}
+
+// NewBinary returns a binary request, body and udh are hex encoded.
+func NewBinary(to, from string, body, udh []byte) (*Request, error) {
+	r := &Request{
+		To:   to,
+		From: from,
+		Type: TypeBinary,
+		Body: hex.EncodeToString(body),
+		UDH:  hex.EncodeToString(udh),
+	}
+	if err := r.ValidateType(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// NewWapPush returns a WAP push request for link with title.
+func NewWapPush(to, from, title, link string) (*Request, error) {
+	r := &Request{
+		To:    to,
+		From:  from,
+		Type:  TypeWapPush,
+		Title: title,
+		URL:   link,
+	}
+	if err := r.ValidateType(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// NewVCard returns a vCard request. vcard must be a complete
+// BEGIN:VCARD ... END:VCARD document.
+func NewVCard(to, from, vcard string) (*Request, error) {
+	r := &Request{
+		To:    to,
+		From:  from,
+		Type:  TypeVCard,
+		Vcard: vcard,
+	}
+	if err := r.ValidateType(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// NewVCal returns a vCalendar request. vcal must be a complete
+// BEGIN:VCALENDAR ... END:VCALENDAR document.
+func NewVCal(to, from, vcal string) (*Request, error) {
+	r := &Request{
+		To:   to,
+		From: from,
+		Type: TypeVCal,
+		Vcal: vcal,
+	}
+	if err := r.ValidateType(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ValidateType checks that fields set are compatible with Type.
+func (r *Request) ValidateType() error {
+	switch r.Type {
+	case "", TypeText, TypeUnicode:
+		if len(r.Text) < 1 {
+			return &FieldError{"Text", "is required for text messages"}
+		}
+		return r.onlyFields("Text")
+	case TypeBinary:
+		if len(r.Body) < 1 {
+			return &FieldError{"Body", "is required for binary messages"}
+		}
+		body, err := hex.DecodeString(r.Body)
+		if err != nil {
+			return &FieldError{"Body", "must be hex encoded"}
+		}
+		udh, err := hex.DecodeString(r.UDH)
+		if err != nil {
+			return &FieldError{"UDH", "must be hex encoded"}
+		}
+		if len(body)+len(udh) > binarySegment {
+			return &FieldError{"Body", fmt.Sprintf("and UDH exceed %d bytes", binarySegment)}
+		}
+		return r.onlyFields("Body", "UDH", "ProtocolID", "DataCoding")
+	case TypeWapPush:
+		if len(r.Title) < 1 {
+			return &FieldError{"Title", "is required for wappush messages"}
+		}
+		if !validate.URL(r.URL) {
+			return &FieldError{"URL", "must be an absolute http URL"}
+		}
+		return r.onlyFields("Title", "URL")
+	case TypeVCard:
+		if !isDocument(r.Vcard, "VCARD") {
+			return &FieldError{"Vcard", "must be a BEGIN:VCARD END:VCARD document"}
+		}
+		return r.onlyFields("Vcard")
+	case TypeVCal:
+		if !isDocument(r.Vcal, "VCALENDAR") {
+			return &FieldError{"Vcal", "must be a BEGIN:VCALENDAR END:VCALENDAR document"}
+		}
+		return r.onlyFields("Vcal")
+	}
+	return &FieldError{"Type", fmt.Sprintf("unknown type [%s]", r.Type)}
+}
+
+// onlyFields returns a FieldError if a type specific field not
+// listed in allowed is set.
+func (r *Request) onlyFields(allowed ...string) error {
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"Text", r.Text},
+		{"Body", r.Body},
+		{"UDH", r.UDH},
+		{"ProtocolID", r.ProtocolID},
+		{"DataCoding", r.DataCoding},
+		{"Title", r.Title},
+		{"URL", r.URL},
+		{"Vcard", r.Vcard},
+		{"Vcal", r.Vcal},
+	}
+	for _, f := range fields {
+		if len(f.value) < 1 || contains(allowed, f.name) {
+			continue
+		}
+		typ := r.Type
+		if len(typ) < 1 {
+			typ = TypeText
+		}
+		return &FieldError{f.name, fmt.Sprintf("not allowed for %s messages", typ)}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for i := range list {
+		if list[i] == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isDocument reports if s is a BEGIN:name ... END:name document.
+func isDocument(s, name string) bool {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	return strings.HasPrefix(s, "BEGIN:"+name) && strings.HasSuffix(s, "END:"+name)
+}
+
+// Validate checks request fields before sending.
+func (r *Request) Validate() error {
+	if !validate.Number(r.To) {
+		return &FieldError{"To", "must be an international number"}
+	}
+	if !validate.Sender(r.From) {
+		return &FieldError{"From", "must be a number up to 15 digits or up to 11 alphanumeric characters"}
+	}
+	if err := r.ValidateType(); err != nil {
+		return err
+	}
+	if len(r.MessageClass) > 0 && !validate.IntRange(r.MessageClass, 0, 3) {
+		return &FieldError{"MessageClass", "must be 0, 1, 2 or 3"}
+	}
+	// validity in milliseconds, 20 seconds to 7 days.
+	if len(r.Validity) > 0 && !validate.IntRange(r.Validity, 20000, 604800000) {
+		return &FieldError{"Validity", "must be between 20000 and 604800000 milliseconds"}
+	}
+	if len(r.StatusReport) > 0 && r.StatusReport != "0" && r.StatusReport != "1" {
+		return &FieldError{"StatusReport", "must be 0 or 1"}
+	}
+	if len(r.Callback) > 0 && !validate.URL(r.Callback) {
+		return &FieldError{"Callback", "must be an absolute http URL"}
+	}
+	if len(r.ProtocolID) > 0 && !validate.IntRange(r.ProtocolID, 0, 255) {
+		return &FieldError{"ProtocolID", "must be between 0 and 255"}
+	}
+	if len(r.DataCoding) > 0 && !validate.IntRange(r.DataCoding, 0, 255) {
+		return &FieldError{"DataCoding", "must be between 0 and 255"}
+	}
+	return nil
+}
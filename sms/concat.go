@@ -0,0 +1,233 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrInvalidConcat returned when concat-total or concat-part are
+// not valid numbers or concat-total differs from previous parts.
+var ErrInvalidConcat = errors.New("nexmo: sms : invalid concat parameters")
+
+// DefaultConcatTTL time incomplete sets are kept when NewReassembler
+// is called without a positive ttl.
+const DefaultConcatTTL = time.Hour
+
+// ConcatStore buffers parts of concatenated inbound messages.
+// Implementations must be safe for concurrent use.
+type ConcatStore interface {
+	// Add saves part m of set key. When every one of total parts
+	// arrived it claims the set and returns its parts ordered by
+	// concat-part, otherwise or when the set is already claimed it
+	// returns nil. The set is kept until Remove. It returns
+	// ErrInvalidConcat when total differs from the set.
+	Add(ctx context.Context, key string, m *InboundMessage, total int, expires time.Time) ([]*InboundMessage, error)
+
+	// Release clears the claim of set key so the next Add of one of
+	// its parts returns the set again.
+	Release(ctx context.Context, key string) error
+
+	// Remove deletes set key.
+	Remove(ctx context.Context, key string) error
+
+	// Expire removes sets expired at now.
+	Expire(ctx context.Context, now time.Time) error
+}
+
+// Reassembler joins concatenated inbound messages.
+type Reassembler struct {
+	store ConcatStore
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewReassembler returns a Reassembler that keeps incomplete sets
+// for ttl, DefaultConcatTTL when ttl is not positive. A nil store
+// uses NewMemoryConcatStore.
+func NewReassembler(store ConcatStore, ttl time.Duration) *Reassembler {
+	if store == nil {
+		store = NewMemoryConcatStore()
+	}
+	if ttl <= 0 {
+		ttl = DefaultConcatTTL
+	}
+	return &Reassembler{
+		store: store,
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+// Add buffers m and returns the complete message when the last part
+// arrives or nil while parts are missing. Messages that are not
+// concatenated are returned as is.
+func (r *Reassembler) Add(ctx context.Context, m *InboundMessage) (*InboundMessage, error) {
+	full, key, err := r.add(ctx, m)
+	if err != nil || full == nil || len(key) < 1 {
+		return full, err
+	}
+	if err := r.store.Remove(ctx, key); err != nil {
+		return nil, err
+	}
+	return full, nil
+}
+
+// add buffers m and returns the complete message and its claimed
+// set key without removing the set from the store.
+func (r *Reassembler) add(ctx context.Context, m *InboundMessage) (*InboundMessage, string, error) {
+	if !m.IsConcat() {
+		return m, "", nil
+	}
+	total, err := strconv.Atoi(m.ConcatTotal)
+	if err != nil || total < 1 {
+		return nil, "", ErrInvalidConcat
+	}
+	part, err := strconv.Atoi(m.ConcatPart)
+	if err != nil || part < 1 || part > total {
+		return nil, "", ErrInvalidConcat
+	}
+	now := r.now()
+	if err := r.store.Expire(ctx, now); err != nil {
+		return nil, "", err
+	}
+	key := m.Msisdn + ":" + m.To + ":" + m.ConcatRef
+	parts, err := r.store.Add(ctx, key, m, total, now.Add(r.ttl))
+	if err != nil || parts == nil {
+		return nil, "", err
+	}
+	full := *parts[0]
+	full.Text = ""
+	full.Data = ""
+	full.ConcatPart = ""
+	for _, p := range parts {
+		full.Text += p.Text
+		full.Data += p.Data
+	}
+	return &full, key, nil
+}
+
+// Handler returns an inbound http.Handler that calls fn once per
+// complete message. Incomplete parts and parts of a message being
+// handled are acknowledged. The set is kept until fn succeeds so a
+// part redelivered by Nexmo after a failure completes the message
+// again.
+func (r *Reassembler) Handler(fn func(ctx context.Context, m *InboundMessage) error) http.Handler {
+	return NewInboundHandler(func(ctx context.Context, m *InboundMessage) error {
+		full, key, err := r.add(ctx, m)
+		if err != nil || full == nil {
+			return err
+		}
+		if err := fn(ctx, full); err != nil {
+			if len(key) > 0 {
+				_ = r.store.Release(ctx, key)
+			}
+			return err
+		}
+		if len(key) < 1 {
+			return nil
+		}
+		return r.store.Remove(ctx, key)
+	})
+}
+
+// MemoryConcatStore in memory ConcatStore.
+type MemoryConcatStore struct {
+	sets map[string]*concatSet
+	sync.Mutex
+}
+
+// concatSet parts received for a concatenated message.
+type concatSet struct {
+	parts   map[int]*InboundMessage
+	total   int
+	expires time.Time
+
+	// claimed set returned complete and not released yet.
+	claimed bool
+}
+
+// NewMemoryConcatStore returns a new in memory ConcatStore.
+func NewMemoryConcatStore() *MemoryConcatStore {
+	return &MemoryConcatStore{
+		sets: make(map[string]*concatSet),
+	}
+}
+
+// Add implements ConcatStore.
+func (s *MemoryConcatStore) Add(ctx context.Context, key string, m *InboundMessage, total int, expires time.Time) ([]*InboundMessage, error) {
+	part, err := strconv.Atoi(m.ConcatPart)
+	if err != nil {
+		return nil, ErrInvalidConcat
+	}
+	s.Lock()
+	defer s.Unlock()
+	set, ok := s.sets[key]
+	if !ok {
+		set = &concatSet{
+			parts:   make(map[int]*InboundMessage, total),
+			total:   total,
+			expires: expires,
+		}
+		s.sets[key] = set
+	}
+	if set.total != total {
+		return nil, ErrInvalidConcat
+	}
+	set.parts[part] = m
+	if len(set.parts) < set.total || set.claimed {
+		return nil, nil
+	}
+	set.claimed = true
+	parts := make([]*InboundMessage, 0, len(set.parts))
+	for _, p := range set.parts {
+		parts = append(parts, p)
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		a, _ := strconv.Atoi(parts[i].ConcatPart)
+		b, _ := strconv.Atoi(parts[j].ConcatPart)
+		return a < b
+	})
+	return parts, nil
+}
+
+// Release implements ConcatStore.
+func (s *MemoryConcatStore) Release(ctx context.Context, key string) error {
+	s.Lock()
+	if set, ok := s.sets[key]; ok {
+		set.claimed = false
+	}
+	s.Unlock()
+	return nil
+}
+
+// Remove implements ConcatStore.
+func (s *MemoryConcatStore) Remove(ctx context.Context, key string) error {
+	s.Lock()
+	delete(s.sets, key)
+	s.Unlock()
+	return nil
+}
+
+// Expire implements ConcatStore.
+func (s *MemoryConcatStore) Expire(ctx context.Context, now time.Time) error {
+	s.Lock()
+	defer s.Unlock()
+	for k, set := range s.sets {
+		if now.After(set.expires) {
+			delete(s.sets, k)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of sets kept.
+func (s *MemoryConcatStore) Len() int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.sets)
+}
@@ -0,0 +1,205 @@
+package sms
+
+import (
+	"unicode/utf16"
+)
+
+const (
+	// TypeText GSM-7 text message.
+	TypeText = "text"
+
+	// TypeUnicode UCS-2 text message.
+	TypeUnicode = "unicode"
+
+	// TypeBinary binary message, Body and UDH are hex encoded.
+	TypeBinary = "binary"
+
+	// TypeWapPush WAP push message with Title and URL.
+	TypeWapPush = "wappush"
+
+	// TypeVCard vCard message.
+	TypeVCard = "vcard"
+
+	// TypeVCal vCalendar message.
+	TypeVCal = "vcal"
+)
+
+const (
+	// gsmSingleSegment septets in a single GSM-7 message.
+	gsmSingleSegment = 160
+
+	// gsmMultiSegment septets per part of a concatenated
+	// GSM-7 message, 7 septets are used by the UDH.
+	gsmMultiSegment = 153
+
+	// ucs2SingleSegment UTF-16 code units in a single UCS-2 message.
+	ucs2SingleSegment = 70
+
+	// ucs2MultiSegment UTF-16 code units per part of a
+	// concatenated UCS-2 message.
+	ucs2MultiSegment = 67
+)
+
+// gsmBasic GSM 03.38 basic character set, escape excluded.
+const gsmBasic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsmExtension GSM 03.38 extension table, each one is sent as an
+// escape followed by the character so it uses two septets.
+const gsmExtension = "\f^{}\\[~]|€"
+
+// gsmExtensionCodes septet of each gsmExtension character.
+var gsmExtensionCodes = []byte{0x0a, 0x14, 0x28, 0x29, 0x2f, 0x3c, 0x3d, 0x3e, 0x40, 0x65}
+
+const (
+	// gsmEscape GSM 03.38 escape to the extension table.
+	gsmEscape = 0x1b
+
+	// gsmCR carriage return septet, used as padding.
+	gsmCR = 0x0d
+)
+
+var (
+	gsmBasicSet     = runeSet(gsmBasic)
+	gsmExtensionSet = runeSet(gsmExtension)
+	gsmCodes        = gsmCodeMap()
+)
+
+// gsmCodeMap returns the septets of every GSM-7 character.
+// gsmBasic is in code order without the escape code.
+func gsmCodeMap() map[rune][]byte {
+	m := make(map[rune][]byte)
+	for i, r := range []rune(gsmBasic) {
+		code := byte(i)
+		if code >= gsmEscape {
+			code++
+		}
+		m[r] = []byte{code}
+	}
+	for i, r := range []rune(gsmExtension) {
+		m[r] = []byte{gsmEscape, gsmExtensionCodes[i]}
+	}
+	return m
+}
+
+func runeSet(s string) map[rune]bool {
+	m := make(map[rune]bool)
+	for _, r := range s {
+		m[r] = true
+	}
+	return m
+}
+
+// Analysis describes how a text is encoded and billed.
+type Analysis struct {
+	// Type TypeText for GSM-7 or TypeUnicode for UCS-2.
+	Type string
+
+	// Length septets for GSM-7 or UTF-16 code units for UCS-2.
+	Length int
+
+	// Segments number of messages needed.
+	Segments int
+
+	// PerSegment capacity of each segment.
+	PerSegment int
+
+	// Remaining units left in the last segment.
+	Remaining int
+}
+
+// Analyze detects GSM-7 or UCS-2 encoding for text and counts
+// the segments needed to send it.
+func Analyze(text string) *Analysis {
+	a := &Analysis{
+		Type: TypeText,
+	}
+	single, multi := gsmSingleSegment, gsmMultiSegment
+	if n, ok := gsmLength(text); ok {
+		a.Length = n
+	} else {
+		a.Type = TypeUnicode
+		a.Length = len(utf16.Encode([]rune(text)))
+		single, multi = ucs2SingleSegment, ucs2MultiSegment
+	}
+	a.PerSegment = single
+	a.Segments = 1
+	if a.Length > single {
+		a.PerSegment = multi
+		a.Segments = (a.Length + multi - 1) / multi
+	}
+	a.Remaining = a.Segments*a.PerSegment - a.Length
+	return a
+}
+
+// IsGSM reports if text can be sent with GSM-7 encoding.
+func IsGSM(text string) bool {
+	_, ok := gsmLength(text)
+	return ok
+}
+
+// gsmLength returns text length in septets or false when text has
+// characters outside GSM-7.
+func gsmLength(text string) (int, bool) {
+	n := 0
+	for _, r := range text {
+		c := gsmSeptets(r)
+		if c == 0 {
+			return 0, false
+		}
+		n += c
+	}
+	return n, true
+}
+
+// gsmSeptets returns septets used by r or 0 if r is not GSM-7.
+func gsmSeptets(r rune) int {
+	switch {
+	case gsmBasicSet[r]:
+		return 1
+	case gsmExtensionSet[r]:
+		return 2
+	}
+	return 0
+}
+
+// encodeGSM returns text as unpacked GSM-7 septets, text must be
+// GSM-7, see IsGSM.
+func encodeGSM(text string) []byte {
+	var septets []byte
+	for _, r := range text {
+		septets = append(septets, gsmCodes[r]...)
+	}
+	return septets
+}
+
+// packSeptets packs septets after fill zero bits, as user data
+// following a UDH. When 7 bits are left over a CR is added so the
+// receiver does not read an extra @.
+func packSeptets(septets []byte, fill int) []byte {
+	if (fill+len(septets)*7)%8 == 1 {
+		septets = append(septets[:len(septets):len(septets)], gsmCR)
+	}
+	bits := fill + len(septets)*7
+	out := make([]byte, (bits+7)/8)
+	pos := fill
+	for _, s := range septets {
+		for b := 0; b < 7; b++ {
+			if s>>uint(b)&1 == 1 {
+				out[pos/8] |= 1 << uint(pos%8)
+			}
+			pos++
+		}
+	}
+	return out
+}
+
+// encodeUCS2 returns text as UTF-16 big endian.
+func encodeUCS2(text string) []byte {
+	units := utf16.Encode([]rune(text))
+	out := make([]byte, 0, len(units)*2)
+	for _, u := range units {
+		out = append(out, byte(u>>8), byte(u))
+	}
+	return out
+}
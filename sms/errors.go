@@ -0,0 +1,102 @@
+package sms
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StatusError is a failed message part inside a Response.
+type StatusError struct {
+	// Code message status.
+	Code Status
+
+	// ErrorText Nexmo error-text.
+	ErrorText string
+
+	// Index message position inside Response.Messages.
+	Index int
+
+	// To message destination.
+	To string
+}
+
+// Error implements error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("nexmo: sms : message [%d] to [%s] status [%s] error text [%s]",
+		e.Index, e.To, string(e.Code), e.ErrorText)
+}
+
+// Temporary reports if the message can be retried later.
+func (e *StatusError) Temporary() bool {
+	return IsTemporary(e.Code)
+}
+
+// Permanent reports if the message will never be delivered
+// as is and must not be retried.
+func (e *StatusError) Permanent() bool {
+	return IsPermanent(e.Code)
+}
+
+// StatusErrors is returned when one or more message parts failed.
+type StatusErrors []*StatusError
+
+// Error implements error interface.
+func (e StatusErrors) Error() string {
+	s := make([]string, len(e))
+	for i := range e {
+		s[i] = e[i].Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Unwrap returns every StatusError so errors.As can match them.
+func (e StatusErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i := range e {
+		errs[i] = e[i]
+	}
+	return errs
+}
+
+// Temporary reports if every failed part is temporary.
+func (e StatusErrors) Temporary() bool {
+	for i := range e {
+		if !e[i].Temporary() {
+			return false
+		}
+	}
+	return len(e) > 0
+}
+
+// IsTemporary reports if status code is a temporary failure,
+// retry later for a positive result.
+func IsTemporary(code Status) bool {
+	return code.IsTemporary()
+}
+
+// IsPermanent reports if status code is a failure that will not
+// be solved retrying, e.g. StatusAbsentSubscriberPermanent.
+func IsPermanent(code Status) bool {
+	return code.IsPermanent()
+}
+
+// Err returns StatusErrors for every failed message or nil when
+// all messages were accepted.
+func (r *Response) Err() error {
+	var errs StatusErrors
+	for i, m := range r.Messages {
+		if m == nil || m.Status == StatusOK {
+			continue
+		}
+		errs = append(errs, &StatusError{
+			Code:      m.Status,
+			ErrorText: m.ErrorText,
+			Index:     i,
+			To:        m.To,
+		})
+	}
+	if len(errs) < 1 {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,58 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/jimmy-go/nexmo/internal/webhook"
+)
+
+// InboundMessage Nexmo inbound message webhook payload.
+//
+// see: https://docs.nexmo.com/messaging/sms-api/api-reference#inbound
+type InboundMessage struct {
+	Type             string `json:"type"`
+	To               string `json:"to"`
+	Msisdn           string `json:"msisdn"`
+	MessageID        string `json:"messageId"`
+	MessageTimestamp string `json:"message-timestamp"`
+	Timestamp        string `json:"timestamp"`
+	Nonce            string `json:"nonce"`
+	Text             string `json:"text"`
+	Keyword          string `json:"keyword"`
+	Concat           string `json:"concat"`
+	ConcatRef        string `json:"concat-ref"`
+	ConcatTotal      string `json:"concat-total"`
+	ConcatPart       string `json:"concat-part"`
+	Data             string `json:"data"`
+	UDH              string `json:"udh"`
+}
+
+// IsConcat reports if message is a part of a concatenated message.
+func (m *InboundMessage) IsConcat() bool {
+	return m.Concat == "true"
+}
+
+// ErrInvalidWebhook returned when a webhook request can not be parsed.
+var ErrInvalidWebhook = errors.New("nexmo: sms : invalid webhook request")
+
+// NewInboundHandler returns an http.Handler for inbound messages
+// sent by Nexmo with GET query, form POST or JSON body. It answers
+// 204 when fn succeeds, 400 for invalid payloads and 500 when fn
+// fails so Nexmo redelivers the message.
+func NewInboundHandler(fn func(ctx context.Context, m *InboundMessage) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m InboundMessage
+		err := webhook.Decode(r, &m)
+		if err != nil || len(m.Msisdn) < 1 || len(m.MessageID) < 1 {
+			http.Error(w, ErrInvalidWebhook.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := fn(r.Context(), &m); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
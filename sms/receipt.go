@@ -0,0 +1,63 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/jimmy-go/nexmo/internal/webhook"
+)
+
+const (
+	// layoutSCTS delivery receipt scts format.
+	layoutSCTS = "0601021504"
+
+	// layoutTimestamp webhook message-timestamp format.
+	layoutTimestamp = "2006-01-02 15:04:05"
+)
+
+// Err returns a StatusError when ErrCode is not StatusOK.
+func (d *DeliveryReceipt) Err() error {
+	if len(d.ErrCode) < 1 || d.ErrCode == StatusOK {
+		return nil
+	}
+	return &StatusError{
+		Code:  d.ErrCode,
+		Index: -1,
+		To:    d.Msisdn,
+	}
+}
+
+// parseTimes fills SCTSTime and MessageTime. Nexmo sends UTC times,
+// invalid values are left zero.
+func (d *DeliveryReceipt) parseTimes() {
+	if t, err := time.Parse(layoutSCTS, d.Scts); err == nil {
+		d.SCTSTime = t
+	}
+	if t, err := time.Parse(layoutTimestamp, d.MessageTimestamp); err == nil {
+		d.MessageTime = t
+	}
+}
+
+// NewDeliveryReceiptHandler returns an http.Handler for delivery
+// receipts sent by Nexmo with GET query, form POST or JSON body.
+// It answers 204 when fn succeeds, 400 for invalid payloads and
+// 500 when fn fails so Nexmo retries.
+//
+// see: https://docs.nexmo.com/messaging/sms-api/api-reference#delivery_receipt
+func NewDeliveryReceiptHandler(fn func(ctx context.Context, d *DeliveryReceipt) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var d DeliveryReceipt
+		err := webhook.Decode(r, &d)
+		if err != nil || len(d.MessageID) < 1 {
+			http.Error(w, ErrInvalidWebhook.Error(), http.StatusBadRequest)
+			return
+		}
+		d.parseTimes()
+		if err := fn(r.Context(), &d); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -3,9 +3,11 @@
 // see: https://docs.nexmo.com/messaging/sms-api/api-reference
 package sms
 
+import "time"
+
 const (
 	// StatusOK 0 - Delivered.
-	StatusOK = "0"
+	StatusOK Status = "0"
 
 	// StatusUnknown 1 - Unknown - either:
 	// * An unknown error was received from the carrier who
@@ -13,7 +15,7 @@ const (
 	// * Depending on the carrier, that to is unknown.
 	// When you see this error, and status is rejected,
 	// always check if to in your request was valid.
-	StatusUnknown = "1"
+	StatusUnknown Status = "1"
 
 	// StatusAbsentSubscriberTemporary 2 - Absent Subscriber
 	// Temporary - this message was not delivered because
@@ -21,30 +23,30 @@ const (
 	// the handset used for to was out of coverage or
 	// switched off. This is a temporary failure,
 	// retry later for a positive result.
-	StatusAbsentSubscriberTemporary = "2"
+	StatusAbsentSubscriberTemporary Status = "2"
 
 	// StatusAbsentSubscriberPermanent 3 - Absent Subscriber
 	// Permanent - to is no longer active, you should
 	// remove this phone number from your database.
-	StatusAbsentSubscriberPermanent = "3"
+	StatusAbsentSubscriberPermanent Status = "3"
 
 	// StatusCallBarredUser 4 - Call barred by user - you should
 	// remove this phone number from your database. If the
 	// user wants to receive messages from you, they need
 	// to contact their carrier directly.
-	StatusCallBarredUser = "4"
+	StatusCallBarredUser Status = "4"
 
 	// StatusPortabilityError 5 - Portability Error - there
 	// is an issue after the user has changed carrier for
 	// to. If the user wants to receive messages from you,
 	// they need to contact their carrier directly.
-	StatusPortabilityError = "5"
+	StatusPortabilityError Status = "5"
 
 	// StatusAntiSpamRejection 6 - Anti-Spam Rejection -
 	// carriers often apply restrictions that block messages
 	// following different criteria. For example, on
 	// SenderID or message content.
-	StatusAntiSpamRejection = "6"
+	StatusAntiSpamRejection Status = "6"
 
 	// StatusHandsetBusy 7 - Handset Busy - the handset
 	// associated with to was not available when this
@@ -53,25 +55,25 @@ const (
 	// If status is Accepted, this message has is in the
 	// retry scheme and will be resent until it expires
 	// in 24-48 hours.
-	StatusHandsetBusy = "7"
+	StatusHandsetBusy Status = "7"
 
 	// StatusNetworkError 8 - Network Error - a network
 	// failure while sending your message. This is a
 	// temporary failure, retry later for a positive result.
-	StatusNetworkError = "8"
+	StatusNetworkError Status = "8"
 
 	// StatusIllegalNumber 9 - Illegal Number - you tried
 	// to send a message to a blacklisted phone number.
 	// That is, the user has already sent a STOP opt-out
 	// message and no longer wishes to receive messages
 	// from you.
-	StatusIllegalNumber = "9"
+	StatusIllegalNumber Status = "9"
 
 	// StatusInvalidMessage 10 - Invalid Message - the
 	// message could not be sent because one of the
 	// parameters in the message was incorrect.
 	// For example, incorrect type or udh.
-	StatusInvalidMessage = "10"
+	StatusInvalidMessage Status = "10"
 
 	// StatusUnroutable 11 - Unroutable - the
 	// chosen route to send your message is not available.
@@ -82,36 +84,36 @@ const (
 	// To resolve this issue either email us at
 	// support@nexmo.com or create a helpdesk ticket
 	// at https://help.nexmo.com.
-	StatusUnroutable = "11"
+	StatusUnroutable Status = "11"
 
 	// StatusDestinationUnreachable 12 - Destination
 	// unreachable - the message could not be delivered to
 	// the phone number.
-	StatusDestinationUnreachable = "12"
+	StatusDestinationUnreachable Status = "12"
 
 	// StatusAgeRestriction 13 - Subscriber Age Restriction
 	// - the carrier blocked this message because the
 	// content is not suitable for to based on
 	// age restrictions.
-	StatusAgeRestriction = "13"
+	StatusAgeRestriction Status = "13"
 
 	// StatusBlockedByCarrier 14 - Number Blocked by Carrier
 	// - the carrier blocked this message. This could be
 	// due to several reasons. For example, to's plan does
 	// not include SMS or the account is suspended.
-	StatusBlockedByCarrier = "14"
+	StatusBlockedByCarrier Status = "14"
 
 	// StatusPrePaidInsufficient 15 - Pre-Paid - Insufficent
 	// funds - to’s pre-paid account does not have enough
 	// credit to receive the message.
-	StatusPrePaidInsufficient = "15"
+	StatusPrePaidInsufficient Status = "15"
 
 	// StatusGeneralError 99 - General Error - there is a
 	// problem with the chosen route to send your message.
 	// To resolve this issue either email us at
 	// support@nexmo.com or create a helpdesk ticket
 	// at https://help.nexmo.com.
-	StatusGeneralError = "99"
+	StatusGeneralError Status = "99"
 )
 
 // Request Nexmo SMS request.
@@ -130,6 +132,7 @@ type Request struct {
 	MessageClass string `url:"message-class"`
 	UDH          string `url:"udh"`
 	ProtocolID   string `url:"protocol-id"`
+	DataCoding   string `url:"data-coding"`
 	Body         string `url:"body"`
 	Title        string `url:"title"`
 	URL          string `url:"url"`
@@ -144,9 +147,21 @@ type Response struct {
 	Messages     []*Message `json:"messages"`
 }
 
+// Throttled reports if any message was rejected because the
+// account throughput limit was exceeded. Only in send responses
+// status 1 means throttled.
+func (r *Response) Throttled() bool {
+	for _, m := range r.Messages {
+		if m != nil && m.Status == StatusUnknown {
+			return true
+		}
+	}
+	return false
+}
+
 // Message inside nexmo response.
 type Message struct {
-	Status           string `json:"status"`
+	Status           Status `json:"status"`
 	MessageID        string `json:"message-id"`
 	To               string `json:"to"`
 	ClientRef        string `json:"client-ref"`
@@ -165,9 +180,16 @@ type DeliveryReceipt struct {
 	MessageID        string `json:"messageId"`
 	Msisdn           string `json:"msisdn"`
 	Status           string `json:"status"`
-	ErrCode          string `json:"err-code"`
+	ErrCode          Status `json:"err-code"`
 	Price            string `json:"price"`
 	Scts             string `json:"scts"`
 	MessageTimestamp string `json:"message-timestamp"`
 	ClientRef        string `json:"client-ref"`
+
+	// SCTSTime parsed Scts, filled by NewDeliveryReceiptHandler.
+	SCTSTime time.Time `json:"-"`
+
+	// MessageTime parsed MessageTimestamp, filled by
+	// NewDeliveryReceiptHandler.
+	MessageTime time.Time `json:"-"`
 }
@@ -0,0 +1,474 @@
+// Package sms contains tests for sms package.
+package sms
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// formRequest returns a form POST request with v as body.
+func formRequest(v url.Values) *http.Request {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(v.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+// jsonRequest returns a JSON POST request with s as body.
+func jsonRequest(s string) *http.Request {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(s))
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+func TestInboundHandler(t *testing.T) {
+	params := url.Values{
+		"msisdn":       {"5215522334455"},
+		"to":           {"12345"},
+		"messageId":    {"02000000E68951D8"},
+		"text":         {"Hello"},
+		"type":         {"text"},
+		"keyword":      {"HELLO"},
+		"concat":       {"true"},
+		"concat-ref":   {"1"},
+		"concat-total": {"2"},
+		"concat-part":  {"1"},
+	}
+	table := []struct {
+		Request  *http.Request
+		Err      error
+		Expected int
+	}{
+		{httptest.NewRequest("GET", "/?"+params.Encode(), nil), nil, http.StatusNoContent},
+		{formRequest(params), nil, http.StatusNoContent},
+		{jsonRequest(`{"msisdn":"5215522334455","to":"12345","messageId":"02000000E68951D8",
+			"text":"Hello","type":"text","keyword":"HELLO","concat":"true",
+			"concat-ref":"1","concat-total":"2","concat-part":"1"}`), nil, http.StatusNoContent},
+		{formRequest(params), errors.New("fail"), http.StatusInternalServerError},
+		{httptest.NewRequest("GET", "/?to=123", nil), nil, http.StatusBadRequest},
+	}
+	for i := range table {
+		x := table[i]
+		var got *InboundMessage
+		h := NewInboundHandler(func(ctx context.Context, m *InboundMessage) error {
+			got = m
+			return x.Err
+		})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, x.Request)
+		if w.Code != x.Expected {
+			t.Errorf("%d : expected status [%d] actual [%d]", i, x.Expected, w.Code)
+			continue
+		}
+		if x.Expected == http.StatusBadRequest {
+			continue
+		}
+		if got.Msisdn != "5215522334455" || got.Text != "Hello" || got.Keyword != "HELLO" ||
+			!got.IsConcat() || got.ConcatTotal != "2" {
+			t.Errorf("%d : unexpected message [%#v]", i, got)
+		}
+	}
+}
+
+func TestDeliveryReceiptHandler(t *testing.T) {
+	params := url.Values{
+		"msisdn":            {"5215522334455"},
+		"to":                {"NexmoTest"},
+		"network-code":      {"33402"},
+		"messageId":         {"02000000E68951D8"},
+		"price":             {"0.00500000"},
+		"status":            {"failed"},
+		"scts":              {"1101181426"},
+		"err-code":          {"7"},
+		"message-timestamp": {"2011-01-18 14:26:00"},
+	}
+	table := []struct {
+		Request  *http.Request
+		Err      error
+		Expected int
+	}{
+		{httptest.NewRequest("GET", "/?"+params.Encode(), nil), nil, http.StatusNoContent},
+		{formRequest(params), nil, http.StatusNoContent},
+		{jsonRequest(`{"msisdn":"5215522334455","to":"NexmoTest","network-code":"33402",
+			"messageId":"02000000E68951D8","price":"0.00500000","status":"failed",
+			"scts":"1101181426","err-code":"7","message-timestamp":"2011-01-18 14:26:00"}`), nil, http.StatusNoContent},
+		{formRequest(params), errors.New("fail"), http.StatusInternalServerError},
+		{jsonRequest(`{`), nil, http.StatusBadRequest},
+	}
+	expected := time.Date(2011, 1, 18, 14, 26, 0, 0, time.UTC)
+	for i := range table {
+		x := table[i]
+		var got *DeliveryReceipt
+		h := NewDeliveryReceiptHandler(func(ctx context.Context, d *DeliveryReceipt) error {
+			got = d
+			return x.Err
+		})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, x.Request)
+		if w.Code != x.Expected {
+			t.Errorf("%d : expected status [%d] actual [%d]", i, x.Expected, w.Code)
+			continue
+		}
+		if x.Expected == http.StatusBadRequest {
+			continue
+		}
+		if !got.SCTSTime.Equal(expected) || !got.MessageTime.Equal(expected) {
+			t.Errorf("%d : unexpected times [%v] [%v]", i, got.SCTSTime, got.MessageTime)
+		}
+		var se *StatusError
+		if !errors.As(got.Err(), &se) || se.Code != StatusHandsetBusy || !se.Temporary() {
+			t.Errorf("%d : unexpected error [%v]", i, got.Err())
+		}
+	}
+}
+
+func TestReassembler(t *testing.T) {
+	store := NewMemoryConcatStore()
+	r := NewReassembler(store, time.Minute)
+	texts := []string{"Hello ", "long ", "world"}
+	var (
+		mu   sync.Mutex
+		full []*InboundMessage
+		wg   sync.WaitGroup
+	)
+	for i := len(texts) - 1; i >= 0; i-- {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m, err := r.Add(context.Background(), &InboundMessage{
+				Msisdn:      "5215522334455",
+				To:          "12345",
+				MessageID:   strconv.Itoa(i),
+				Text:        texts[i],
+				Concat:      "true",
+				ConcatRef:   "9",
+				ConcatTotal: "3",
+				ConcatPart:  strconv.Itoa(i + 1),
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if m != nil {
+				mu.Lock()
+				full = append(full, m)
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if len(full) != 1 || full[0].Text != "Hello long world" {
+		t.Fatalf("unexpected messages [%v]", full)
+	}
+	if store.Len() != 0 {
+		t.Errorf("expected empty store actual [%d]", store.Len())
+	}
+
+	// incomplete sets expire.
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	_, _ = r.Add(context.Background(), &InboundMessage{Msisdn: "1", Concat: "true",
+		ConcatRef: "1", ConcatTotal: "2", ConcatPart: "1"})
+	now = now.Add(2 * time.Minute)
+	_, _ = r.Add(context.Background(), &InboundMessage{Msisdn: "2", Concat: "true",
+		ConcatRef: "1", ConcatTotal: "2", ConcatPart: "1"})
+	if store.Len() != 1 {
+		t.Errorf("expected 1 set actual [%d]", store.Len())
+	}
+
+	_, err := r.Add(context.Background(), &InboundMessage{Concat: "true", ConcatTotal: "2", ConcatPart: "3"})
+	if err != ErrInvalidConcat {
+		t.Errorf("expected [%v] actual [%v]", ErrInvalidConcat, err)
+	}
+	_, err = r.Add(context.Background(), &InboundMessage{Msisdn: "2", Concat: "true",
+		ConcatRef: "1", ConcatTotal: "3", ConcatPart: "2"})
+	if err != ErrInvalidConcat {
+		t.Errorf("expected total mismatch [%v] actual [%v]", ErrInvalidConcat, err)
+	}
+
+	// non positive ttl uses default.
+	if r := NewReassembler(nil, 0); r.ttl != DefaultConcatTTL {
+		t.Errorf("expected ttl [%v] actual [%v]", DefaultConcatTTL, r.ttl)
+	}
+}
+
+func TestReassemblerHandlerFailure(t *testing.T) {
+	store := NewMemoryConcatStore()
+	fail := true
+	var (
+		got   string
+		calls int
+		h     http.Handler
+		send  func(part, text string) int
+	)
+	h = NewReassembler(store, time.Minute).Handler(func(ctx context.Context, m *InboundMessage) error {
+		calls++
+		// a part redelivered while fn runs is acknowledged.
+		if calls == 1 {
+			if code := send("2", "world"); code != http.StatusNoContent {
+				t.Errorf("expected [%d] actual [%d]", http.StatusNoContent, code)
+			}
+		}
+		if fail {
+			return errors.New("database down")
+		}
+		got = m.Text
+		return nil
+	})
+	send = func(part, text string) int {
+		q := url.Values{
+			"msisdn": {"5215522334455"}, "to": {"12345"}, "messageId": {"0" + part},
+			"text": {text}, "type": {"text"}, "concat": {"true"}, "concat-ref": {"7"},
+			"concat-total": {"2"}, "concat-part": {part},
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/inbound?"+q.Encode(), nil))
+		return w.Code
+	}
+	if code := send("1", "Hello "); code != http.StatusNoContent {
+		t.Fatalf("expected [%d] actual [%d]", http.StatusNoContent, code)
+	}
+	if code := send("2", "world"); code != http.StatusInternalServerError || calls != 1 {
+		t.Fatalf("expected [%d] once actual [%d] [%d] calls", http.StatusInternalServerError, code, calls)
+	}
+	// Nexmo redelivers only the last part.
+	fail = false
+	if code := send("2", "world"); code != http.StatusNoContent || got != "Hello world" || calls != 2 {
+		t.Errorf("expected [Hello world] actual [%d] [%s] [%d] calls", code, got, calls)
+	}
+	if store.Len() != 0 {
+		t.Errorf("expected empty store actual [%d]", store.Len())
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	table := []struct {
+		Text      string
+		Type      string
+		Length    int
+		Segments  int
+		Remaining int
+	}{
+		{"Hello world!", TypeText, 12, 1, 148},
+		{"Price 10€ [ok]", TypeText, 17, 1, 143},
+		{strings.Repeat("a", 160), TypeText, 160, 1, 0},
+		{strings.Repeat("a", 161), TypeText, 161, 2, 145},
+		{strings.Repeat("{", 80), TypeText, 160, 1, 0},
+		{"Hola señor, ¿cómo está?", TypeUnicode, 23, 1, 47},
+		{"Hi 😀", TypeUnicode, 5, 1, 65},
+		{strings.Repeat("ж", 71), TypeUnicode, 71, 2, 63},
+	}
+	for i := range table {
+		x := table[i]
+		a := Analyze(x.Text)
+		if a.Type != x.Type || a.Length != x.Length || a.Segments != x.Segments || a.Remaining != x.Remaining {
+			t.Errorf("%d : unexpected analysis [%+v]", i, a)
+		}
+	}
+}
+
+func TestSplitText(t *testing.T) {
+	table := []struct {
+		Text  string
+		Ref   int
+		Parts int
+		UDH   string
+		Limit int
+	}{
+		{"short", 1, 1, "", 160},
+		{strings.Repeat("a", 200), 7, 2, "0500030702", 153},
+		{strings.Repeat("a", 200), 300, 2, "060804012c02", 152},
+		{strings.Repeat("€", 100), 1, 2, "0500030102", 153},
+		{strings.Repeat("ж", 100), 1, 2, "0500030102", 67},
+		{strings.Repeat("😀", 50), 1, 2, "0500030102", 67},
+	}
+	for i := range table {
+		x := table[i]
+		parts, err := SplitText(&Request{To: "5215522334455", From: "NexmoTest", Text: x.Text}, x.Ref)
+		if err != nil || len(parts) != x.Parts {
+			t.Errorf("%d : expected parts [%d] actual [%d] [%v]", i, x.Parts, len(parts), err)
+			continue
+		}
+		if x.Parts == 1 {
+			if parts[0].Text != x.Text || parts[0].Type != TypeText || len(parts[0].UDH) > 0 {
+				t.Errorf("%d : unexpected single part [%+v]", i, parts[0])
+			}
+			continue
+		}
+		var joined string
+		for j, p := range parts {
+			if err := p.Validate(); err != nil || p.Type != TypeBinary || len(p.Text) > 0 {
+				t.Errorf("%d : invalid part [%+v] [%v]", i, p, err)
+			}
+			if p.UDH[:len(p.UDH)-2] != x.UDH {
+				t.Errorf("%d : unexpected udh [%s]", i, p.UDH)
+			}
+			if p.UDH[len(p.UDH)-2:] != fmt.Sprintf("%02x", j+1) {
+				t.Errorf("%d : unexpected sequence [%s]", i, p.UDH)
+			}
+			text := decodePart(t, p)
+			if !utf8.ValidString(text) {
+				t.Errorf("%d : invalid utf8 part [%s]", i, text)
+			}
+			if a := Analyze(text); a.Length > x.Limit {
+				t.Errorf("%d : part too long [%d]", i, a.Length)
+			}
+			joined += text
+		}
+		if joined != x.Text {
+			t.Errorf("%d : parts do not join original text [%s]", i, joined)
+		}
+	}
+	if b := hex.EncodeToString(packSeptets(encodeGSM("hellohello"), 0)); b != "e8329bfd4697d9ec37" {
+		t.Errorf("expected [e8329bfd4697d9ec37] actual [%s]", b)
+	}
+	if _, err := SplitText(&Request{Text: "a"}, 70000); err != ErrInvalidConcatRef {
+		t.Errorf("expected [%v] actual [%v]", ErrInvalidConcatRef, err)
+	}
+}
+
+// decodePart decodes the text of a binary part made by SplitText.
+func decodePart(t *testing.T, p *Request) string {
+	body, _ := hex.DecodeString(p.Body)
+	udh, _ := hex.DecodeString(p.UDH)
+	if p.DataCoding == DataCodingUCS2 {
+		units := make([]uint16, len(body)/2)
+		for i := range units {
+			units[i] = uint16(body[2*i])<<8 | uint16(body[2*i+1])
+		}
+		return string(utf16.Decode(units))
+	}
+	if p.DataCoding != DataCodingGSM || p.ProtocolID != "0" {
+		t.Errorf("unexpected data coding [%s] protocol id [%s]", p.DataCoding, p.ProtocolID)
+	}
+	codes := make(map[string]rune)
+	for r, c := range gsmCodes {
+		codes[string(c)] = r
+	}
+	fill := (7 - len(udh)*8%7) % 7
+	var text []rune
+	escape := false
+	for pos := fill; pos+7 <= len(body)*8; pos += 7 {
+		var s byte
+		for b := 0; b < 7; b++ {
+			if body[(pos+b)/8]>>uint((pos+b)%8)&1 == 1 {
+				s |= 1 << uint(b)
+			}
+		}
+		switch {
+		case s == gsmEscape:
+			escape = true
+		case escape:
+			text = append(text, codes[string([]byte{gsmEscape, s})])
+			escape = false
+		default:
+			text = append(text, codes[string([]byte{s})])
+		}
+	}
+	// drop CR padding.
+	return strings.TrimSuffix(string(text), "\r")
+}
+
+func TestSplitBinary(t *testing.T) {
+	body := make([]byte, 300)
+	parts, err := SplitBinary(&Request{To: "5215522334455"}, body, 1)
+	if err != nil || len(parts) != 3 {
+		t.Fatalf("expected 3 parts actual [%d] [%v]", len(parts), err)
+	}
+	if parts[0].Type != TypeBinary || len(parts[0].Body) != 134*2 || parts[2].UDH != "050003010303" {
+		t.Errorf("unexpected parts [%+v] [%+v]", parts[0], parts[2])
+	}
+	parts, err = SplitBinary(&Request{To: "5215522334455", UDH: "0605040b8423f0"}, []byte{0xca, 0xfe}, 1)
+	if err != nil || len(parts) != 1 || parts[0].UDH != "0605040b8423f0" {
+		t.Errorf("expected caller udh kept actual [%+v] [%v]", parts, err)
+	}
+}
+
+func TestBuilders(t *testing.T) {
+	r, err := NewBinary("5215522334455", "NexmoTest", []byte{0xca, 0xfe}, []byte{0x05, 0x00, 0x03, 0x01, 0x01, 0x01})
+	if err != nil || r.Body != "cafe" || r.UDH != "050003010101" || r.Type != TypeBinary {
+		t.Errorf("unexpected binary [%+v] [%v]", r, err)
+	}
+	_, err = NewBinary("5215522334455", "NexmoTest", make([]byte, 140), []byte{0x05, 0x00, 0x03, 0x01, 0x01, 0x01})
+	if fe, ok := err.(*FieldError); !ok || fe.Field != "Body" {
+		t.Errorf("expected Body field error actual [%v]", err)
+	}
+	_, err = NewWapPush("5215522334455", "NexmoTest", "Site", "http://example.com")
+	if err != nil {
+		t.Errorf("wappush : err [%v]", err)
+	}
+	_, err = NewWapPush("5215522334455", "NexmoTest", "Site", "example.com")
+	if fe, ok := err.(*FieldError); !ok || fe.Field != "URL" {
+		t.Errorf("expected URL field error actual [%v]", err)
+	}
+	_, err = NewVCard("5215522334455", "NexmoTest", "BEGIN:VCARD\nVERSION:2.1\nFN:Jimmy\nEND:VCARD")
+	if err != nil {
+		t.Errorf("vcard : err [%v]", err)
+	}
+	_, err = NewVCal("5215522334455", "NexmoTest", "BEGIN:VCARD\nEND:VCARD")
+	if fe, ok := err.(*FieldError); !ok || fe.Field != "Vcal" {
+		t.Errorf("expected Vcal field error actual [%v]", err)
+	}
+	err = (&Request{To: "5215522334455", From: "+447700900000", Type: TypeText, Text: "Hello"}).Validate()
+	if err != nil {
+		t.Errorf("number sender with + : err [%v]", err)
+	}
+	err = (&Request{To: "5215522334455", From: "++447700900000", Type: TypeText, Text: "Hello"}).Validate()
+	if fe, ok := err.(*FieldError); !ok || fe.Field != "From" {
+		t.Errorf("expected From field error actual [%v]", err)
+	}
+	err = (&Request{Type: TypeText, Text: "Hello", Vcard: "BEGIN:VCARD"}).ValidateType()
+	if fe, ok := err.(*FieldError); !ok || fe.Field != "Vcard" {
+		t.Errorf("expected Vcard field error actual [%v]", err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	table := []struct {
+		Input     string
+		Expected  Status
+		Temporary bool
+		Permanent bool
+	}{
+		{`"0"`, StatusOK, false, false},
+		{`0`, StatusOK, false, false},
+		{`"7"`, StatusHandsetBusy, true, false},
+		{`8`, StatusNetworkError, true, false},
+		{`"9"`, StatusIllegalNumber, false, true},
+	}
+	for i, x := range table {
+		var m Message
+		if err := json.Unmarshal([]byte(`{"status":`+x.Input+`}`), &m); err != nil {
+			t.Fatalf("%d : unmarshal : %v", i, err)
+		}
+		if m.Status != x.Expected {
+			t.Errorf("%d : expected [%v] actual [%v]", i, x.Expected, m.Status)
+		}
+		if m.Status.IsTemporary() != x.Temporary || m.Status.IsPermanent() != x.Permanent {
+			t.Errorf("%d : unexpected temporary [%v] permanent [%v]", i, m.Status.IsTemporary(), m.Status.IsPermanent())
+		}
+	}
+	if StatusHandsetBusy.String() != "handset busy" || len(StatusHandsetBusy.Description()) < 1 {
+		t.Errorf("unexpected string [%s] description [%s]", StatusHandsetBusy, StatusHandsetBusy.Description())
+	}
+	var m Message
+	if err := json.Unmarshal([]byte(`{"status":null}`), &m); err != nil || m.Status == StatusOK || !m.Status.IsPermanent() {
+		t.Errorf("expected null status not ok actual [%q] [%v]", m.Status, err)
+	}
+	b, err := json.Marshal(&Message{Status: StatusHandsetBusy})
+	if err != nil || !strings.Contains(string(b), `"status":"7"`) {
+		t.Errorf("unexpected json [%s] [%v]", b, err)
+	}
+}
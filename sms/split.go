@@ -0,0 +1,144 @@
+package sms
+
+import (
+	"encoding/hex"
+	"errors"
+)
+
+const (
+	// binarySegment bytes of user data in a single message.
+	binarySegment = 140
+
+	// maxParts concatenated messages upper limit.
+	maxParts = 255
+
+	// DataCodingGSM data coding scheme of GSM-7 binary parts.
+	DataCodingGSM = "0"
+
+	// DataCodingUCS2 data coding scheme of UCS-2 binary parts.
+	DataCodingUCS2 = "8"
+)
+
+var (
+	// ErrTooManyParts returned when a message needs more than
+	// 255 parts.
+	ErrTooManyParts = errors.New("nexmo: sms : message needs more than 255 parts")
+
+	// ErrInvalidConcatRef returned when reference is not in
+	// 0-65535 range.
+	ErrInvalidConcatRef = errors.New("nexmo: sms : invalid concat reference")
+)
+
+// concatUDH returns the concatenation UDH for part seq of total.
+// References above 255 use the 16 bit information element.
+func concatUDH(ref, total, seq int) []byte {
+	if ref > 0xff {
+		return []byte{0x06, 0x08, 0x04, byte(ref >> 8), byte(ref), byte(total), byte(seq)}
+	}
+	return []byte{0x05, 0x00, 0x03, byte(ref), byte(total), byte(seq)}
+}
+
+// udhLength returns concat UDH length for ref.
+func udhLength(ref int) int {
+	return len(concatUDH(ref, 1, 1))
+}
+
+// SplitText splits r.Text in concatenated binary requests with
+// concat UDH and reference ref. Each part Body is r.Text encoded as
+// packed GSM-7 or UCS-2, detected with Analyze, and DataCoding is
+// set accordingly. Text that fits in a single message is returned
+// as one text or unicode request. Parts never split a GSM escape
+// sequence or a surrogate pair.
+func SplitText(r *Request, ref int) ([]*Request, error) {
+	if ref < 0 || ref > 0xffff {
+		return nil, ErrInvalidConcatRef
+	}
+	a := Analyze(r.Text)
+	if a.Segments == 1 {
+		part := *r
+		part.Type = a.Type
+		return []*Request{&part}, nil
+	}
+	// GSM-7 user data starts at a septet boundary after the UDH.
+	udhBits := udhLength(ref) * 8
+	fill := (7 - udhBits%7) % 7
+	limit := (binarySegment*8 - udhBits - fill) / 7
+	units := gsmSeptets
+	if a.Type == TypeUnicode {
+		limit = (binarySegment*8 - udhBits) / 16
+		units = utf16Units
+	}
+	var texts []string
+	start, n := 0, 0
+	for i, c := range r.Text {
+		u := units(c)
+		if n+u > limit {
+			texts = append(texts, r.Text[start:i])
+			start, n = i, 0
+		}
+		n += u
+	}
+	texts = append(texts, r.Text[start:])
+	if len(texts) > maxParts {
+		return nil, ErrTooManyParts
+	}
+	parts := make([]*Request, len(texts))
+	for i := range texts {
+		part := *r
+		part.Type = TypeBinary
+		part.Text = ""
+		part.ProtocolID = "0"
+		if a.Type == TypeUnicode {
+			part.DataCoding = DataCodingUCS2
+			part.Body = hex.EncodeToString(encodeUCS2(texts[i]))
+		} else {
+			part.DataCoding = DataCodingGSM
+			part.Body = hex.EncodeToString(packSeptets(encodeGSM(texts[i]), fill))
+		}
+		part.UDH = hex.EncodeToString(concatUDH(ref, len(texts), i+1))
+		parts[i] = &part
+	}
+	return parts, nil
+}
+
+// SplitBinary splits body in concatenated binary requests based on
+// r with concat UDH and reference ref. A body that fits in a single
+// message keeps r.UDH.
+func SplitBinary(r *Request, body []byte, ref int) ([]*Request, error) {
+	if ref < 0 || ref > 0xffff {
+		return nil, ErrInvalidConcatRef
+	}
+	if len(body) <= binarySegment {
+		part := *r
+		part.Type = TypeBinary
+		part.Body = hex.EncodeToString(body)
+		return []*Request{&part}, nil
+	}
+	size := binarySegment - udhLength(ref)
+	total := (len(body) + size - 1) / size
+	if total > maxParts {
+		return nil, ErrTooManyParts
+	}
+	parts := make([]*Request, total)
+	for i := range parts {
+		end := (i + 1) * size
+		if end > len(body) {
+			end = len(body)
+		}
+		part := *r
+		part.Type = TypeBinary
+		part.Text = ""
+		part.Body = hex.EncodeToString(body[i*size : end])
+		part.UDH = hex.EncodeToString(concatUDH(ref, total, i+1))
+		parts[i] = &part
+	}
+	return parts, nil
+}
+
+// utf16Units returns UTF-16 code units used by c.
+func utf16Units(c rune) int {
+	if c > 0xffff {
+		return 2
+	}
+	return 1
+}
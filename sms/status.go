@@ -0,0 +1,103 @@
+package sms
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/jimmy-go/nexmo/internal/status"
+)
+
+// Status SMS message status and delivery receipt error code,
+// Nexmo sends it as a string.
+type Status string
+
+// statusCodes names and describes every Status constant.
+var statusCodes = map[Status]struct {
+	name        string
+	description string
+}{
+	StatusOK:                        {"delivered", "Delivered."},
+	StatusUnknown:                   {"unknown", "Unknown carrier error or unknown destination, in send responses the account throughput was exceeded."},
+	StatusAbsentSubscriberTemporary: {"absent subscriber temporary", "Destination temporarily unavailable, retry later."},
+	StatusAbsentSubscriberPermanent: {"absent subscriber permanent", "Destination is no longer active."},
+	StatusCallBarredUser:            {"call barred by user", "Destination barred incoming messages."},
+	StatusPortabilityError:          {"portability error", "Destination changed carrier and can not be reached."},
+	StatusAntiSpamRejection:         {"anti-spam rejection", "Carrier blocked the message by sender or content."},
+	StatusHandsetBusy:               {"handset busy", "Handset not available, retry later."},
+	StatusNetworkError:              {"network error", "Network failure while sending, retry later."},
+	StatusIllegalNumber:             {"illegal number", "Destination opted out with STOP."},
+	StatusInvalidMessage:            {"invalid message", "A message parameter is incorrect, e.g. type or udh."},
+	StatusUnroutable:                {"unroutable", "No route available for the destination network."},
+	StatusDestinationUnreachable:    {"destination unreachable", "Message could not be delivered to the phone number."},
+	StatusAgeRestriction:            {"subscriber age restriction", "Carrier blocked the content by age restriction."},
+	StatusBlockedByCarrier:          {"number blocked by carrier", "Carrier blocked the message."},
+	StatusPrePaidInsufficient:       {"pre-paid insufficient funds", "Destination pre-paid account has not enough credit."},
+	StatusGeneralError:              {"general error", "Problem with the chosen route."},
+}
+
+// String returns the status name, e.g. "handset busy".
+func (s Status) String() string {
+	if c, ok := statusCodes[s]; ok {
+		return c.name
+	}
+	return "status(" + string(s) + ")"
+}
+
+// Description returns a short description of the status.
+func (s Status) Description() string {
+	return statusCodes[s].description
+}
+
+// IsTemporary reports if status is a temporary failure, retry
+// later for a positive result.
+func (s Status) IsTemporary() bool {
+	switch s {
+	case StatusAbsentSubscriberTemporary,
+		StatusHandsetBusy,
+		StatusNetworkError:
+		return true
+	}
+	return false
+}
+
+// IsPermanent reports if status is a failure that will not be
+// solved retrying, e.g. StatusAbsentSubscriberPermanent.
+func (s Status) IsPermanent() bool {
+	return s != StatusOK && !s.IsTemporary()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Status) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it accepts 7 and "7".
+// null leaves s unchanged.
+func (s *Status) UnmarshalJSON(b []byte) error {
+	if status.IsNull(b) {
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var v string
+		err := json.Unmarshal(b, &v)
+		*s = Status(v)
+		return err
+	}
+	n, err := status.ParseJSON(b)
+	if err != nil {
+		return err
+	}
+	*s = Status(strconv.Itoa(n))
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Status) UnmarshalText(b []byte) error {
+	*s = Status(b)
+	return nil
+}
@@ -0,0 +1,127 @@
+package text2speech
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/jimmy-go/nexmo/internal/status"
+)
+
+// Status Nexmo TextToSpeech response status code, Nexmo sends
+// it as a string.
+//
+// see: https://docs.nexmo.com/voice/text-to-speech/response
+type Status string
+
+const (
+	// StatusOK 0 - Success.
+	StatusOK Status = "0"
+
+	// StatusThrottled 1 - Throttled, back-off and retry.
+	StatusThrottled Status = "1"
+
+	// StatusMissingParams 2 - Missing mandatory parameters.
+	StatusMissingParams Status = "2"
+
+	// StatusInvalidParams 3 - Invalid parameter value.
+	StatusInvalidParams Status = "3"
+
+	// StatusInvalidCredentials 4 - Invalid api_key or api_secret.
+	StatusInvalidCredentials Status = "4"
+
+	// StatusInternalError 5 - Nexmo internal error.
+	StatusInternalError Status = "5"
+
+	// StatusInvalidMessage 6 - Unable to process request.
+	StatusInvalidMessage Status = "6"
+
+	// StatusNumberBarred 7 - Destination is blacklisted.
+	StatusNumberBarred Status = "7"
+
+	// StatusPartnerAccountBarred 8 - Account is barred.
+	StatusPartnerAccountBarred Status = "8"
+
+	// StatusPartnerQuotaExceeded 9 - Not enough credit.
+	StatusPartnerQuotaExceeded Status = "9"
+
+	// StatusAccountNotEnabled 11 - Account not enabled for Voice.
+	StatusAccountNotEnabled Status = "11"
+
+	// StatusMessageTooLong 12 - Request too long.
+	StatusMessageTooLong Status = "12"
+
+	// StatusCommunicationFailed 13 - Call could not be placed.
+	StatusCommunicationFailed Status = "13"
+
+	// StatusInvalidSignature 14 - Invalid request signature.
+	StatusInvalidSignature Status = "14"
+
+	// StatusInvalidSenderAddress 15 - Caller ID not allowed.
+	StatusInvalidSenderAddress Status = "15"
+)
+
+// String returns the status name, e.g. "throttled".
+func (s Status) String() string {
+	n, err := strconv.Atoi(string(s))
+	if err != nil {
+		return string(s)
+	}
+	return status.Name(n)
+}
+
+// Description returns the Nexmo description of the status.
+func (s Status) Description() string {
+	n, err := strconv.Atoi(string(s))
+	if err != nil {
+		return ""
+	}
+	return status.Description(n)
+}
+
+// IsTemporary reports if the request can be retried later.
+func (s Status) IsTemporary() bool {
+	n, err := strconv.Atoi(string(s))
+	return err == nil && status.Temporary(n)
+}
+
+// IsPermanent reports if the request failed and retrying
+// will not help.
+func (s Status) IsPermanent() bool {
+	return s != StatusOK && !s.IsTemporary()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Status) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(string(s))), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it accepts 1 and "1".
+// null leaves s unchanged.
+func (s *Status) UnmarshalJSON(b []byte) error {
+	if status.IsNull(b) {
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var v string
+		err := json.Unmarshal(b, &v)
+		*s = Status(v)
+		return err
+	}
+	n, err := status.ParseJSON(b)
+	if err != nil {
+		return err
+	}
+	*s = Status(strconv.Itoa(n))
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Status) UnmarshalText(b []byte) error {
+	*s = Status(b)
+	return nil
+}
@@ -26,6 +26,6 @@ type Request struct {
 type Response struct {
 	CallID    string `json:"call_id"`
 	To        string `json:"to"`
-	Status    string `json:"status"`
+	Status    Status `json:"status"`
 	ErrorText string `json:"error_text"`
 }
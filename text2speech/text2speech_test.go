@@ -0,0 +1,68 @@
+// Package text2speech contains tests for text2speech package.
+package text2speech
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCallbackHandler(t *testing.T) {
+	var got *CallbackEvent
+	h := NewCallbackHandler(func(ctx context.Context, e *CallbackEvent) error {
+		got = e
+		if e.Status == CallbackFailed {
+			return errors.New("retry")
+		}
+		return nil
+	})
+	body := "call-id=abc&to=12015550123&status=ok&call-start=2017-01-01+12:00:05&call-duration=12&call-price=0.004"
+	r := httptest.NewRequest("POST", "/tts", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent || got == nil {
+		t.Fatalf("expected [%d] actual [%d]", http.StatusNoContent, w.Code)
+	}
+	start := time.Date(2017, 1, 1, 12, 0, 5, 0, time.UTC)
+	if got.Status != CallbackOK || got.Duration != 12*time.Second || got.Price != 0.004 || !got.StartTime.Equal(start) {
+		t.Errorf("unexpected callback [%+v]", got)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/tts?call-id=abc&status=failed", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected [%v] actual [%v]", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	table := []struct {
+		Input    string
+		Expected Status
+	}{
+		{`{"status":"0"}`, StatusOK},
+		{`{"status":13}`, StatusCommunicationFailed},
+		{`{"status":null}`, ""},
+	}
+	for i, x := range table {
+		var r Response
+		if err := json.Unmarshal([]byte(x.Input), &r); err != nil {
+			t.Fatalf("%d : unmarshal : %v", i, err)
+		}
+		if r.Status != x.Expected {
+			t.Errorf("%d : expected [%v] actual [%v]", i, x.Expected, r.Status)
+		}
+	}
+	if !StatusCommunicationFailed.IsTemporary() || !StatusInvalidParams.IsPermanent() || StatusOK.IsPermanent() {
+		t.Errorf("unexpected temporary or permanent classification")
+	}
+	if StatusInvalidParams.String() != "invalid params" || len(StatusInvalidParams.Description()) < 1 {
+		t.Errorf("unexpected string [%s]", StatusInvalidParams)
+	}
+}
@@ -0,0 +1,51 @@
+package text2speech
+
+import (
+	"fmt"
+
+	"github.com/jimmy-go/nexmo/internal/validate"
+)
+
+// FieldError names the request field that is not valid.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+// Error implements error interface.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("nexmo: text2speech : field [%s] %s", e.Field, e.Reason)
+}
+
+// Validate checks request fields before sending.
+func (r *Request) Validate() error {
+	if !validate.Number(r.To) {
+		return &FieldError{"To", "must be an international number"}
+	}
+	if len(r.From) > 0 && !validate.Number(r.From) {
+		return &FieldError{"From", "must be an international number"}
+	}
+	if len(r.Text) < 1 {
+		return &FieldError{"Text", "is required"}
+	}
+	// zero Repeat uses Nexmo default.
+	if r.Repeat != 0 && (r.Repeat < 1 || r.Repeat > 10) {
+		return &FieldError{"Repeat", "must be between 1 and 10"}
+	}
+	if len(r.Voice) > 0 && r.Voice != "male" && r.Voice != "female" {
+		return &FieldError{"Voice", "must be male or female"}
+	}
+	if len(r.MachineDetection) > 0 && r.MachineDetection != "true" && r.MachineDetection != "hangup" {
+		return &FieldError{"MachineDetection", "must be true or hangup"}
+	}
+	if len(r.MachineTimeout) > 0 && !validate.IntRange(r.MachineTimeout, 400, 10000) {
+		return &FieldError{"MachineTimeout", "must be between 400 and 10000 milliseconds"}
+	}
+	if len(r.Callback) > 0 && !validate.URL(r.Callback) {
+		return &FieldError{"Callback", "must be an absolute http URL"}
+	}
+	if len(r.CallbackMethod) > 0 && !validate.Method(r.CallbackMethod) {
+		return &FieldError{"CallbackMethod", "must be GET or POST"}
+	}
+	return nil
+}
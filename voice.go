@@ -0,0 +1,146 @@
+package nexmo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-querystring/query"
+	"github.com/jimmy-go/nexmo/voice"
+)
+
+// ErrNoApplication returned when Voice API methods are called on a
+// client without WithJWTGenerator.
+var ErrNoApplication = errors.New("nexmo: voice : client has no application JWT generator")
+
+// pathCalls Voice API calls resource.
+const pathCalls = "/v1/calls"
+
+// CreateCall creates an outbound call with Voice API.
+//
+// see: https://docs.nexmo.com/voice/voice-api/api-reference#call_create
+func (x *Nexmo) CreateCall(ctx context.Context, r *voice.CreateCallRequest) (*voice.CreateCallResponse, error) {
+	var res *voice.CreateCallResponse
+	err := x.doJSON(ctx, http.MethodPost, pathCalls, nil, r, &res)
+	return res, err
+}
+
+// ListCalls returns calls matching filter, filter can be nil.
+//
+// see: https://docs.nexmo.com/voice/voice-api/api-reference#call_retrieve
+func (x *Nexmo) ListCalls(ctx context.Context, filter *voice.ListCallsFilter) (*voice.CallList, error) {
+	v := url.Values{}
+	if filter != nil {
+		var err error
+		v, err = query.Values(filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var res *voice.CallList
+	err := x.doJSON(ctx, http.MethodGet, pathCalls, v, nil, &res)
+	return res, err
+}
+
+// GetCall returns call uuid details.
+func (x *Nexmo) GetCall(ctx context.Context, uuid string) (*voice.Call, error) {
+	var res *voice.Call
+	err := x.doJSON(ctx, http.MethodGet, pathCalls+"/"+url.PathEscape(uuid), nil, nil, &res)
+	return res, err
+}
+
+// ModifyCall hangs up, mutes, earmuffs or transfers call uuid.
+//
+// see: https://docs.nexmo.com/voice/voice-api/api-reference#call_modify_single
+func (x *Nexmo) ModifyCall(ctx context.Context, uuid string, r *voice.ModifyRequest) error {
+	return x.doJSON(ctx, http.MethodPut, pathCalls+"/"+url.PathEscape(uuid), nil, r, nil)
+}
+
+// StreamCall plays audio files into call uuid.
+//
+// see: https://docs.nexmo.com/voice/voice-api/api-reference#stream_put
+func (x *Nexmo) StreamCall(ctx context.Context, uuid string, r *voice.StreamRequest) (*voice.StreamResponse, error) {
+	var res *voice.StreamResponse
+	err := x.doJSON(ctx, http.MethodPut, pathCalls+"/"+url.PathEscape(uuid)+"/stream", nil, r, &res)
+	return res, err
+}
+
+// StopStreamCall stops audio streamed into call uuid.
+func (x *Nexmo) StopStreamCall(ctx context.Context, uuid string) (*voice.StreamResponse, error) {
+	var res *voice.StreamResponse
+	err := x.doJSON(ctx, http.MethodDelete, pathCalls+"/"+url.PathEscape(uuid)+"/stream", nil, nil, &res)
+	return res, err
+}
+
+// doJSON internal Voice API request doer. It authenticates with an
+// application JWT and sends body as JSON.
+func (x *Nexmo) doJSON(ctx context.Context, method, path string, p url.Values, body, dst interface{}) error {
+	if x.jwt == nil {
+		return ErrNoApplication
+	}
+	token, err := x.jwt.Token()
+	if err != nil {
+		return err
+	}
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+	uri := strings.TrimSuffix(x.apiURL, "/") + path
+	if len(p) > 0 {
+		uri += "?" + p.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, uri, rd)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(x.userAgent) > 0 {
+		req.Header.Set("User-Agent", x.userAgent)
+	}
+	resp, err := x.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	if x.logger != nil {
+		x.logger.Printf("Nexmo : doJSON : %s %s status [%d] body [%s]", method, req.URL.Path, resp.StatusCode, b)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := newAPIError("voice", resp.StatusCode, b, nil)
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		return apiErr
+	}
+	if dst == nil || len(b) < 1 {
+		return nil
+	}
+	if err := json.Unmarshal(b, dst); err != nil {
+		return newAPIError("voice", resp.StatusCode, b, err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package voice
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidKey returned when private key is not a PEM
+	// encoded RSA key.
+	ErrInvalidKey = errors.New("nexmo: voice : invalid private key")
+
+	// ErrInvalidToken returned when a token is malformed or its
+	// signature does not match.
+	ErrInvalidToken = errors.New("nexmo: voice : invalid token")
+
+	// ErrExpiredToken returned when token exp is in the past.
+	ErrExpiredToken = errors.New("nexmo: voice : expired token")
+)
+
+// DefaultTTL token lifetime when JWTGenerator.TTL is zero.
+const DefaultTTL = 15 * time.Minute
+
+// JWTGenerator creates RS256 tokens to authenticate as a Nexmo
+// application.
+//
+// see: https://docs.nexmo.com/tools/application-api/application-security
+type JWTGenerator struct {
+	// ApplicationID application_id claim.
+	ApplicationID string
+
+	// PrivateKey application private key.
+	PrivateKey *rsa.PrivateKey
+
+	// TTL token lifetime. Zero uses DefaultTTL.
+	TTL time.Duration
+
+	// Claims extra claims added to every token, e.g. acl or sub.
+	Claims map[string]interface{}
+
+	// Now returns current time. Nil uses time.Now.
+	Now func() time.Time
+}
+
+// NewJWTGenerator returns a JWTGenerator for application appID with
+// a PEM encoded PKCS#1 or PKCS#8 RSA private key.
+func NewJWTGenerator(appID string, privateKey []byte) (*JWTGenerator, error) {
+	key, err := ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &JWTGenerator{
+		ApplicationID: appID,
+		PrivateKey:    key,
+	}, nil
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS#1 or PKCS#8 RSA key.
+func ParsePrivateKey(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	key, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// Token returns a new signed token.
+func (g *JWTGenerator) Token() (string, error) {
+	if g.PrivateKey == nil {
+		return "", ErrInvalidKey
+	}
+	now := time.Now()
+	if g.Now != nil {
+		now = g.Now()
+	}
+	ttl := g.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	claims := make(map[string]interface{}, len(g.Claims)+4)
+	for k, v := range g.Claims {
+		claims[k] = v
+	}
+	claims["application_id"] = g.ApplicationID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(ttl).Unix()
+	claims["jti"] = hex.EncodeToString(jti)
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signing := encode(header) + "." + encode(payload)
+	sum := sha256.Sum256([]byte(signing))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, g.PrivateKey, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+	return signing + "." + encode(sig), nil
+}
+
+// VerifyToken checks an RS256 token signature and expiration with
+// key and returns its claims. Useful for local test servers.
+func VerifyToken(token string, key *rsa.PublicKey, now time.Time) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	b, err := decode(parts[0])
+	if err != nil || json.Unmarshal(b, &header) != nil || header.Alg != "RS256" {
+		return nil, ErrInvalidToken
+	}
+	sig, err := decode(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], sig) != nil {
+		return nil, ErrInvalidToken
+	}
+	b, err = decode(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok || now.Unix() >= int64(exp) {
+		return nil, ErrExpiredToken
+	}
+	return claims, nil
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
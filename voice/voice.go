@@ -0,0 +1,157 @@
+// Package voice contains Nexmo Voice API v2 requests, responses and
+// application JWT authentication.
+//
+// see: https://docs.nexmo.com/voice/voice-api
+package voice
+
+import "time"
+
+const (
+	// ActionHangup terminates the call.
+	ActionHangup = "hangup"
+
+	// ActionMute mutes the call.
+	ActionMute = "mute"
+
+	// ActionUnmute unmutes the call.
+	ActionUnmute = "unmute"
+
+	// ActionEarmuff stops the call hearing audio.
+	ActionEarmuff = "earmuff"
+
+	// ActionUnearmuff resumes audio to the call.
+	ActionUnearmuff = "unearmuff"
+
+	// ActionTransfer transfers the call to a new NCCO.
+	ActionTransfer = "transfer"
+)
+
+// Endpoint call source or destination.
+type Endpoint struct {
+	// Type phone, websocket or sip.
+	Type        string            `json:"type"`
+	Number      string            `json:"number,omitempty"`
+	DtmfAnswer  string            `json:"dtmfAnswer,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	ContentType string            `json:"content-type,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// Phone returns a phone Endpoint.
+func Phone(number string) Endpoint {
+	return Endpoint{Type: "phone", Number: number}
+}
+
+// WebSocket returns a websocket Endpoint streaming audio with
+// contentType, e.g. audio/l16;rate=16000.
+func WebSocket(uri, contentType string, headers map[string]string) Endpoint {
+	return Endpoint{Type: "websocket", URI: uri, ContentType: contentType, Headers: headers}
+}
+
+// SIP returns a sip Endpoint.
+func SIP(uri string, headers map[string]string) Endpoint {
+	return Endpoint{Type: "sip", URI: uri, Headers: headers}
+}
+
+// CreateCallRequest Voice API create call request.
+//
+// see: https://docs.nexmo.com/voice/voice-api/api-reference#call_create
+type CreateCallRequest struct {
+	To               []Endpoint `json:"to"`
+	From             Endpoint   `json:"from"`
+	AnswerURL        []string   `json:"answer_url"`
+	AnswerMethod     string     `json:"answer_method,omitempty"`
+	EventURL         []string   `json:"event_url,omitempty"`
+	EventMethod      string     `json:"event_method,omitempty"`
+	MachineDetection string     `json:"machine_detection,omitempty"`
+	LengthTimer      int        `json:"length_timer,omitempty"`
+	RingingTimer     int        `json:"ringing_timer,omitempty"`
+}
+
+// CreateCallResponse Voice API create call response.
+type CreateCallResponse struct {
+	UUID             string `json:"uuid"`
+	ConversationUUID string `json:"conversation_uuid"`
+	Direction        string `json:"direction"`
+	Status           string `json:"status"`
+}
+
+// Call Voice API call details.
+type Call struct {
+	UUID             string     `json:"uuid"`
+	ConversationUUID string     `json:"conversation_uuid"`
+	To               Endpoint   `json:"to"`
+	From             Endpoint   `json:"from"`
+	Status           string     `json:"status"`
+	Direction        string     `json:"direction"`
+	Rate             string     `json:"rate"`
+	Price            string     `json:"price"`
+	Duration         string     `json:"duration"`
+	StartTime        *time.Time `json:"start_time"`
+	EndTime          *time.Time `json:"end_time"`
+	Network          string     `json:"network"`
+}
+
+// ListCallsFilter Voice API list calls filter, empty fields are
+// not sent.
+type ListCallsFilter struct {
+	Status           string    `url:"status,omitempty"`
+	DateStart        time.Time `url:"date_start,omitempty"`
+	DateEnd          time.Time `url:"date_end,omitempty"`
+	PageSize         int       `url:"page_size,omitempty"`
+	RecordIndex      int       `url:"record_index,omitempty"`
+	Order            string    `url:"order,omitempty"`
+	ConversationUUID string    `url:"conversation_uuid,omitempty"`
+}
+
+// CallList Voice API list calls response.
+type CallList struct {
+	Count       int `json:"count"`
+	PageSize    int `json:"page_size"`
+	RecordIndex int `json:"record_index"`
+	Embedded    struct {
+		Calls []*Call `json:"calls"`
+	} `json:"_embedded"`
+}
+
+// Calls returns calls in the page.
+func (l *CallList) Calls() []*Call {
+	return l.Embedded.Calls
+}
+
+// ModifyRequest Voice API modify call request.
+type ModifyRequest struct {
+	Action      string       `json:"action"`
+	Destination *Destination `json:"destination,omitempty"`
+}
+
+// Destination transfer destination.
+type Destination struct {
+	// Type ncco.
+	Type string   `json:"type"`
+	URL  []string `json:"url"`
+}
+
+// Transfer returns a ModifyRequest transferring the call to the
+// NCCO served at url.
+func Transfer(url string) *ModifyRequest {
+	return &ModifyRequest{
+		Action: ActionTransfer,
+		Destination: &Destination{
+			Type: "ncco",
+			URL:  []string{url},
+		},
+	}
+}
+
+// StreamRequest Voice API stream audio into a call request.
+type StreamRequest struct {
+	StreamURL []string `json:"stream_url"`
+	Loop      int      `json:"loop,omitempty"`
+}
+
+// StreamResponse Voice API stream response.
+type StreamResponse struct {
+	Message string `json:"message"`
+	UUID    string `json:"uuid"`
+}
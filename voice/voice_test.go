@@ -0,0 +1,49 @@
+// Package voice contains tests for voice package.
+package voice
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+func TestJWTGenerator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	g, err := NewJWTGenerator("app-id", pemKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1500000000, 0)
+	g.Now = func() time.Time { return now }
+	g.TTL = time.Minute
+	g.Claims = map[string]interface{}{"sub": "jimmy"}
+	token, err := g.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := VerifyToken(token, &key.PublicKey, now.Add(30*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims["application_id"] != "app-id" || claims["sub"] != "jimmy" ||
+		claims["exp"] != float64(now.Add(time.Minute).Unix()) || len(claims["jti"].(string)) != 32 {
+		t.Errorf("unexpected claims [%v]", claims)
+	}
+	if _, err := VerifyToken(token, &key.PublicKey, now.Add(2*time.Minute)); err != ErrExpiredToken {
+		t.Errorf("expected [%v] actual [%v]", ErrExpiredToken, err)
+	}
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	if _, err := VerifyToken(token, &other.PublicKey, now); err != ErrInvalidToken {
+		t.Errorf("expected [%v] actual [%v]", ErrInvalidToken, err)
+	}
+	if _, err := NewJWTGenerator("app-id", []byte("not a key")); err != ErrInvalidKey {
+		t.Errorf("expected [%v] actual [%v]", ErrInvalidKey, err)
+	}
+}